@@ -1,5 +1,5 @@
 module github.com/quenbyako/set
 
-go 1.18
+go 1.23
 
 require golang.org/x/exp v0.0.0-20230210204819-062eb4c674ab
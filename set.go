@@ -7,17 +7,44 @@ package set
 
 import (
 	"fmt"
+	"iter"
 	"strings"
 )
 
 // Set is describing a Set. Sets are an unordered, unique list of values.
 type Set[T any] interface {
 	Add(items ...T) Set[T]
+	// Insert includes the item to the set and reports whether it was new,
+	// i.e. it wasn't in the set before.
+	Insert(item T) bool
 	Remove(items ...T) Set[T]
+	// AddSlice is like Add, but takes a slice, and makes room for all its
+	// items at once, before adding them.
+	AddSlice(items []T) Set[T]
+	// RemoveSlice is like Remove, but takes a slice.
+	RemoveSlice(items []T) Set[T]
+	// AddSliceCount is like AddSlice, but returns the number of the items
+	// which were new, i.e. weren't in the set before. Duplicates within the
+	// slice are counted once. Threadsafe sets add all the items under a
+	// single write lock, so the count is consistent with concurrent writers.
+	AddSliceCount(items []T) int
+	// RemoveChanged is like Remove, but reports whether any of the items was
+	// in the set, e.g. to decide whether the set has to be saved. Threadsafe
+	// sets check and remove under a single write lock.
+	RemoveChanged(items ...T) bool
 	Pop() (T, bool)
+	// PopN deletes and returns up to n items from the set. If the set has
+	// less than n items, all of them are returned. If n <= 0, an empty slice
+	// is returned and the set isn't modified.
+	PopN(n int) []T
 	Has(items ...T) bool
+	// HasAny reports whether at least one of the passed items exists, unlike
+	// Has, which needs all of them. It returns false if nothing is passed.
+	HasAny(items ...T) bool
 	// Size returns the number of items in a set.
 	Size() int
+	// Len is an alias of Size, for code expecting the conventional name.
+	Len() int
 	// Clear removes all items from the set.
 	Clear()
 	// IsEmpty reports whether the Set is empty.
@@ -31,14 +58,37 @@ type Set[T any] interface {
 	// each set member. Traversal will continue until all items in the Set have
 	// been visited, or if the closure returns false.
 	Each(func(T) bool) bool
+	// All returns an iterator over the items in the Set, which yields every
+	// member exactly once.
+	All() iter.Seq[T]
 	String() string
+	// List returns the items in a newly allocated slice, of both length and
+	// capacity Size(), so modifying it never affects the set. An empty set
+	// gives an empty, non-nil slice, which marshals to a JSON array.
 	List() []T
 	// Copy returns a new Set with a copy of s.
 	Copy() Set[T]
+	// Filter returns a new Set of the same kind as s, which contains only the
+	// items the provided function returned true for. The Set s is not
+	// modified.
+	Filter(func(T) bool) Set[T]
 	// Merge is like Union, however it modifies the current set it's applied on
 	// with the given t set.
 	Merge(s Set[T]) Set[T]
+	// MergeChanged is like Merge, but reports whether any item of the given
+	// set was new to the current one. A set evicting items, like the one
+	// created by NewWindowed, reports a change even if its size stays the
+	// same.
+	MergeChanged(s Set[T]) bool
+	// Separate removes the items of the given set from the current set it's
+	// applied on. Unlike Difference and Without, it modifies the set.
 	Separate(s Set[T]) Set[T]
+	// RemoveIf deletes every item the provided function returns true for. The
+	// underlying Set s is modified, no copy is made.
+	RemoveIf(func(T) bool) Set[T]
+	// Retain deletes every item the provided function returns false for. It's
+	// the complement of RemoveIf.
+	Retain(func(T) bool) Set[T]
 }
 
 // helpful to not write everywhere struct{}{}
@@ -49,25 +99,44 @@ type null = struct{}
 // NonThreadSafe. The default is ThreadSafe.
 func New[T comparable](items ...T) Set[T]       { return newTS(items...) }
 func NewNonTS[T comparable](items ...T) Set[T]  { return newNonTS(items...) }
-func NewAny[T Hashable](items ...T) Set[T]      { panic("unimplemented") }
+func NewAny[T Hashable](items ...T) Set[T]      { return newAnyTS[T](items...) }
 func NewAnyNonTS[T Hashable](items ...T) Set[T] { return newAnyNonTS[T](items...) }
 
+// NewFunc creates a new threadsafe Set of items, which are neither comparable
+// nor Hashable, e.g. structs with slice fields. The items are bucketed by
+// their keys, and the items sharing a key are told apart by eq. Equal items
+// must have equal keys, while distinct items should rarely share one.
+func NewFunc[T any](eq func(a, b T) bool, key func(T) uint64, items ...T) Set[T] {
+	return newFuncTS(eq, key, items...)
+}
+
+// NewWithCap is like New, but preallocates room for capacity items, so adding
+// up to that many items doesn't grow the set.
+func NewWithCap[T comparable](capacity int, items ...T) Set[T] {
+	return newTSWithCap(capacity, items...)
+}
+
+// NewNonTSWithCap is like NewNonTS, but preallocates room for capacity items.
+func NewNonTSWithCap[T comparable](capacity int, items ...T) Set[T] {
+	return newNonTSWithCap(capacity, items...)
+}
+
 // Union is the merger of multiple sets. It returns a new set with all the
 // elements present in all the sets that are passed.
 //
 // The dynamic type of the returned set is determined by the first passed set's
-// implementation of the New() method.
-func Union[T any](set1, set2 Set[T], sets ...Set[T]) Set[T] {
+// implementation of the New() method. Every other set is merged into it at
+// once, so a threadsafe result is locked once per set, not once per item.
+// Bitsets are united word by word.
+func Union[T comparable](set1, set2 Set[T], sets ...Set[T]) Set[T] {
+	if bs, ok := asBitsets(set1, set2, sets...); ok {
+		return any(bitsetUnion(bs)).(Set[T])
+	}
+
 	u := set1.Copy()
-	set2.Each(func(item T) bool {
-		u.Add(item)
-		return true
-	})
+	u.Merge(set2)
 	for _, set := range sets {
-		set.Each(func(item T) bool {
-			u.Add(item)
-			return true
-		})
+		u.Merge(set)
 	}
 
 	return u
@@ -76,45 +145,337 @@ func Union[T any](set1, set2 Set[T], sets ...Set[T]) Set[T] {
 // Difference returns a new set which contains items which are in in the first
 // set but not in the others. Unlike the Difference() method you can use this
 // function separately with multiple sets.
-func Difference[T any](set1, set2 Set[T], sets ...Set[T]) Set[T] {
+func Difference[T comparable](set1, set2 Set[T], sets ...Set[T]) Set[T] {
 	s := set1.Copy()
 	s.Separate(set2)
 	for _, set := range sets {
-		s.Separate(set) // seperate is thread safe
+		s.Separate(set) // seperate is thread safe, and locks s only once
 	}
 	return s
 }
 
 // Intersection returns a new set which contains items that only exist in all given sets.
-func Intersection[T any](set1, set2 Set[T], sets ...Set[T]) Set[T] {
-	all := Union(set1, set2, sets...)
-	result := Union(set1, set2, sets...)
+//
+// The smallest of the sets drives the iteration, and every its item is looked
+// up in the others, so intersecting with a tiny set is cheap regardless of the
+// size of the rest. The dynamic type of the returned set is the one of the
+// first passed set. Bitsets are intersected word by word.
+func Intersection[T comparable](set1, set2 Set[T], sets ...Set[T]) Set[T] {
+	if bs, ok := asBitsets(set1, set2, sets...); ok {
+		return any(bitsetIntersect(bs)).(Set[T])
+	}
 
-	all.Each(func(item T) bool {
-		if !set1.Has(item) || !set2.Has(item) {
-			result.Remove(item)
+	all := append([]Set[T]{set1, set2}, sets...)
+
+	smallest := 0
+	for i, set := range all {
+		if set.Size() < all[smallest].Size() {
+			smallest = i
 		}
+	}
 
-		for _, set := range sets {
-			if !set.Has(item) {
-				result.Remove(item)
-			}
+	// The smallest set is snapshotted rather than traversed, so no two sets
+	// are ever locked at once, in whichever order the sets are passed.
+	var common []T
+	for _, item := range all[smallest].List() {
+		if hasAll(all, smallest, item) {
+			common = append(common, item)
 		}
-		return true
-	})
-	return result
+	}
+
+	// added at once, so a threadsafe result is locked only once
+	return emptyLike(set1).Add(common...)
+}
+
+// hasAll reports whether every set, but the one at index skip, has the item.
+func hasAll[T any](sets []Set[T], skip int, item T) bool {
+	for i, set := range sets {
+		if i != skip && !set.Has(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IntersectionN is like Intersection, but accepts any number of sets. Given
+// no sets, it returns a new empty set, as created by New. Given a single set,
+// it returns its copy. Otherwise the result is of the first set's kind.
+func IntersectionN[T comparable](sets ...Set[T]) Set[T] {
+	switch len(sets) {
+	case 0:
+		return New[T]()
+	case 1:
+		return sets[0].Copy()
+	default:
+		return Intersection(sets[0], sets[1], sets[2:]...)
+	}
+}
+
+// Without returns a new set of the same kind as s, which contains the items of
+// s missing in t. Unlike the Separate method, it doesn't modify s.
+func Without[T any](s, t Set[T]) Set[T] {
+	return s.Filter(func(item T) bool { return !t.Has(item) })
+}
+
+// Diff returns the changes turning old into new: added holds the items of new
+// missing in old, while removed holds the items of old missing in new. Each
+// set is traversed once, and the results are of the same kind as new and old
+// respectively.
+func Diff[T comparable](old, new Set[T]) (added, removed Set[T]) {
+	return Without(new, old), Without(old, new)
 }
 
 // SymmetricDifference returns a new set which s is the difference of items which are in
 // one of either, but not in both.
-func SymmetricDifference[T any](s, t Set[T]) Set[T] {
+func SymmetricDifference[T comparable](s, t Set[T]) Set[T] {
 	u := Difference(s, t)
 	v := Difference(t, s)
 	return Union(u, v)
 }
 
-func stringSet[T any](s Set[T]) string {
-	l := s.List()
+// SymmetricDifferenceCount returns the number of items which are in one of s
+// and t, but not in both, without building any set. Threadsafe sets are
+// read-locked for the whole count.
+func SymmetricDifferenceCount[T comparable](s, t Set[T]) int {
+	s, t, unlock := rLockPair(s, t)
+	defer unlock()
+
+	n := 0
+	count := func(a, b Set[T]) {
+		a.Each(func(item T) bool {
+			if !b.Has(item) {
+				n++
+			}
+			return true
+		})
+	}
+	count(s, t)
+	count(t, s)
+
+	return n
+}
+
+// Equal reports whether a and b have the same items, regardless of their
+// implementations. Unlike the IsEqual method it accepts nil sets, which are
+// equal to each other and to empty sets. Threadsafe sets are read-locked in a
+// canonical order for the whole comparison.
+//
+// The sets must be of the same size, and every item of a is looked up with
+// the Has method of b, so b decides which items are the same: a set created by
+// New compares them with the == operator, while one created by NewAny by
+// their hashes and Equal methods. For items whose Equal method agrees with
+// the == operator, as it should, the order of a and b doesn't matter.
+func Equal[T any](a, b Set[T]) bool {
+	switch {
+	case a == nil && b == nil:
+		return true
+	case a == nil:
+		return b.IsEmpty()
+	case b == nil:
+		return a.IsEmpty()
+	}
+
+	a, b, unlock := rLockPair(a, b)
+	defer unlock()
+
+	return a.Size() == b.Size() && a.Each(func(item T) bool { return b.Has(item) })
+}
+
+// EqualExcept reports whether a and b hold the same items once the items of
+// ignore are left out of consideration on both sides. Unlike filtering both
+// sets first, it doesn't allocate any intermediate set.
+func EqualExcept[T comparable](a, b Set[T], ignore Set[T]) bool {
+	contains := func(s, t Set[T]) bool {
+		return s.Each(func(item T) bool {
+			return ignore.Has(item) || t.Has(item)
+		})
+	}
+
+	return contains(a, b) && contains(b, a)
+}
+
+// IsDisjoint reports whether a and b have no items in common. It iterates the
+// smaller set and stops at the first shared item, without allocating.
+// Threadsafe sets are read-locked for the whole check.
+func IsDisjoint[T any](a, b Set[T]) bool {
+	a, b, unlock := rLockPair(a, b)
+	defer unlock()
+
+	if a.Size() > b.Size() {
+		a, b = b, a
+	}
+
+	return a.Each(func(item T) bool { return !b.Has(item) })
+}
+
+// IsProperSubset is like the IsSubset method, it tests whether t is a subset
+// of s, but also requires t to be smaller, so equal sets aren't proper subsets
+// of each other. Threadsafe sets are read-locked for the whole check.
+func IsProperSubset[T any](s, t Set[T]) bool {
+	s, t, unlock := rLockPair(s, t)
+	defer unlock()
+
+	return t.Size() < s.Size() && s.IsSubset(t)
+}
+
+// IsProperSuperset is like the IsSuperset method, it tests whether t is a
+// superset of s, but also requires t to be bigger.
+func IsProperSuperset[T any](s, t Set[T]) bool { return IsProperSubset(t, s) }
+
+// EachSnapshot is like Each, but calls f for the items of a snapshot of s,
+// taken under a single read lock, which is released before the first call.
+// So f may modify s, and a slow f doesn't block other writers, but the items
+// visited may be slightly stale. It reports whether all the items were
+// visited.
+func EachSnapshot[T any](s Set[T], f func(T) bool) bool {
+	for _, item := range s.List() {
+		if !f(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CopyInto replaces the items of dst with the items of s, like Copy does for
+// a new set. The sets created by New and NewNonTS reuse their memory, so
+// copying into the same scratch set repeatedly doesn't allocate. If both sets
+// are threadsafe, they are locked in the canonical order.
+func CopyInto[T comparable](s, dst Set[T]) {
+	if dst == s {
+		return
+	}
+
+	switch d := dst.(type) {
+	case *setm[T]:
+		d.copyFrom(s)
+	case *set[T]:
+		d.ClearKeepCap()
+		d.Merge(s)
+	default:
+		dst.Clear()
+		dst.Merge(s)
+	}
+}
+
+// AllOfType narrows a set of arbitrary items to a set of T. It returns a new
+// non-threadsafe set and true if every item of s is of type T, otherwise
+// false is returned.
+func AllOfType[T comparable](s Set[any]) (Set[T], bool) {
+	u := newNonTS[T]()
+	ok := s.Each(func(item any) bool {
+		typed, ok := item.(T)
+		if ok {
+			u.Add(typed)
+		}
+		return ok
+	})
+	if !ok {
+		return nil, false
+	}
+
+	return u, true
+}
+
+// insertCount inserts the items to s one by one and returns the number of the
+// new ones. It implements AddSliceCount of the sets, which have no faster way.
+func insertCount[T any](s Set[T], items []T) int {
+	n := 0
+	for _, item := range items {
+		if s.Insert(item) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// removeChanged removes the items from s and reports whether its size changed.
+// It implements RemoveChanged of the sets, which aren't threadsafe.
+func removeChanged[T any](s Set[T], items []T) bool {
+	before := s.Size()
+	s.Remove(items...)
+
+	return s.Size() != before
+}
+
+// mergeChanged inserts the items of t to s one by one and reports whether any
+// of them was new. Unlike comparing the sizes, it notices the new items of the
+// sets evicting the old ones.
+func mergeChanged[T any](s, t Set[T]) bool {
+	changed := false
+	for _, item := range t.List() {
+		if s.Insert(item) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// StringSlice returns the items of s as a slice of strings. It's a typed
+// shorthand for s.List().
+func StringSlice(s Set[string]) []string { return s.List() }
+
+// IntSlice returns the items of s as a slice of ints. It's a typed shorthand
+// for s.List().
+func IntSlice(s Set[int]) []int { return s.List() }
+
+// popN calls pop up to n times, but no more than size times, and collects the
+// popped items.
+func popN[T any](n, size int, pop func() (T, bool)) []T {
+	if n > size {
+		n = size
+	}
+	if n <= 0 {
+		return []T{}
+	}
+
+	items := make([]T, 0, n)
+	for len(items) < n {
+		item, ok := pop()
+		if !ok {
+			break
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// emptyLike returns a new empty set of the same kind as s.
+func emptyLike[T comparable](s Set[T]) Set[T] {
+	switch s.(type) {
+	case *setm[T]:
+		return newTS[T]()
+	case *set[T]:
+		return newNonTS[T]()
+	default:
+		return s.Filter(func(T) bool { return false })
+	}
+}
+
+// not negates the predicate f.
+func not[T any](f func(T) bool) func(T) bool {
+	return func(item T) bool { return !f(item) }
+}
+
+// each calls f for every item yielded by seq, until f returns false. It
+// reports whether all the items were visited.
+func each[T any](seq iter.Seq[T], f func(T) bool) bool {
+	completed := true
+	seq(func(item T) bool {
+		completed = f(item)
+		return completed
+	})
+
+	return completed
+}
+
+func stringSet[T any](s Set[T]) string { return stringItems(s.List()) }
+
+func stringItems[T any](l []T) string {
 	t := make([]string, 0, len(l))
 	for _, item := range l {
 
@@ -0,0 +1,97 @@
+package set
+
+// Bag is a multiset: like a set, but it counts how many times every item was
+// added. A Bag isn't threadsafe. The zero value is an empty bag ready to use.
+type Bag[T comparable] struct {
+	m    map[T]int
+	size int
+}
+
+// NewBag creates a new Bag, which has every passed item counted once.
+func NewBag[T comparable](items ...T) *Bag[T] {
+	b := &Bag[T]{m: make(map[T]int, len(items))}
+	for _, item := range items {
+		b.Add(item, 1)
+	}
+
+	return b
+}
+
+// Add adds n occurrences of the item to the bag. Non-positive n is ignored.
+func (b *Bag[T]) Add(item T, n int) {
+	if n <= 0 {
+		return
+	}
+	if b.m == nil {
+		b.m = make(map[T]int)
+	}
+
+	b.m[item] += n
+	b.size += n
+}
+
+// Remove removes up to n occurrences of the item from the bag. The item is
+// gone once its count drops to zero. Non-positive n is ignored.
+func (b *Bag[T]) Remove(item T, n int) {
+	count, ok := b.m[item]
+	if !ok || n <= 0 {
+		return
+	}
+
+	if n >= count {
+		delete(b.m, item)
+		b.size -= count
+		return
+	}
+	b.m[item] = count - n
+	b.size -= n
+}
+
+// Count returns the number of occurrences of the item in the bag.
+func (b *Bag[T]) Count(item T) int { return b.m[item] }
+
+// Size returns the number of occurrences of all the items in the bag.
+func (b *Bag[T]) Size() int { return b.size }
+
+// Distinct returns the number of unique items in the bag.
+func (b *Bag[T]) Distinct() int { return len(b.m) }
+
+// Each traverses the unique items in the bag along with their counts.
+// Traversal will continue until all items have been visited, or if the
+// closure returns false.
+func (b *Bag[T]) Each(f func(item T, count int) bool) bool {
+	for item, count := range b.m {
+		if !f(item, count) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Union returns a new bag with every item of b and o, counted as many times
+// as in the bag having more of it.
+func (b *Bag[T]) Union(o *Bag[T]) *Bag[T] {
+	u := &Bag[T]{m: make(map[T]int, max(len(b.m), len(o.m)))}
+	for item, count := range b.m {
+		u.Add(item, max(count, o.m[item]))
+	}
+	for item, count := range o.m {
+		if _, ok := b.m[item]; !ok {
+			u.Add(item, count)
+		}
+	}
+
+	return u
+}
+
+// Intersection returns a new bag with the items present in both b and o,
+// counted as many times as in the bag having less of it.
+func (b *Bag[T]) Intersection(o *Bag[T]) *Bag[T] {
+	u := &Bag[T]{m: make(map[T]int)}
+	for item, count := range b.m {
+		u.Add(item, min(count, o.m[item]))
+	}
+
+	return u
+}
@@ -0,0 +1,48 @@
+package set
+
+import "testing"
+
+func TestBag_Count(t *testing.T) {
+	b := NewBag("go", "set", "go")
+	b.Add("bag", 3)
+	b.Add("go", 0)
+
+	if b.Count("go") != 2 || b.Count("bag") != 3 || b.Count("none") != 0 {
+		t.Error("Add: unexpected counts", b.m)
+	}
+	if b.Size() != 6 || b.Distinct() != 3 {
+		t.Errorf("Size: expected six items, three distinct, got %d and %d", b.Size(), b.Distinct())
+	}
+
+	b.Remove("bag", 2)
+	b.Remove("go", 5)
+	if b.Count("bag") != 1 || b.Count("go") != 0 || b.Size() != 2 || b.Distinct() != 2 {
+		t.Error("Remove: unexpected counts", b.m)
+	}
+
+	var zero Bag[int]
+	zero.Add(1, 2)
+	if zero.Count(1) != 2 {
+		t.Error("Add: zero bag should be ready to use")
+	}
+}
+
+func TestBag_Algebra(t *testing.T) {
+	a := NewBag[string]()
+	a.Add("x", 3)
+	a.Add("y", 1)
+	b := NewBag[string]()
+	b.Add("x", 1)
+	b.Add("y", 2)
+	b.Add("z", 4)
+
+	u := a.Union(b)
+	if u.Count("x") != 3 || u.Count("y") != 2 || u.Count("z") != 4 || u.Size() != 9 {
+		t.Error("Union: expected the maximum counts, got", u.m)
+	}
+
+	i := a.Intersection(b)
+	if i.Count("x") != 1 || i.Count("y") != 1 || i.Count("z") != 0 || i.Distinct() != 2 {
+		t.Error("Intersection: expected the minimum counts, got", i.m)
+	}
+}
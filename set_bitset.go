@@ -0,0 +1,362 @@
+package set
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// bitset is a set of non-negative integers backed by a bitmap, where the bit
+// i of the word i/64 tells whether i is in the set.
+type bitset struct {
+	words []uint64
+	size  int
+}
+
+var _ Set[int] = (*bitset)(nil)
+
+// NewBitset creates a new non-threadsafe Set of non-negative integers, backed
+// by a bitmap instead of a map. It's preallocated for the items from 0 to
+// maxValue, and takes a bit per each of them, so it fits dense small domains,
+// like flags, well. Bigger items grow the bitmap as needed, while adding a
+// negative item panics.
+func NewBitset(maxValue int) Set[int] {
+	return &bitset{words: make([]uint64, wordsFor(maxValue))}
+}
+
+// wordsFor returns the number of words needed to hold the items up to and
+// including maxValue.
+func wordsFor(maxValue int) int { return max(maxValue, -1)/64 + 1 }
+
+func (s *bitset) has(item int) bool {
+	w := item / 64
+	return item >= 0 && w < len(s.words) && s.words[w]&(1<<(item%64)) != 0
+}
+
+// insert includes the item, growing the bitmap if needed, and reports whether
+// it wasn't there before.
+func (s *bitset) insert(item int) bool {
+	if item < 0 {
+		panic("set: negative item added to a bitset")
+	}
+	w, bit := item/64, uint64(1)<<(item%64)
+	if w >= len(s.words) {
+		s.words = append(s.words, make([]uint64, w+1-len(s.words))...)
+	}
+	if s.words[w]&bit != 0 {
+		return false
+	}
+	s.words[w] |= bit
+	s.size++
+
+	return true
+}
+
+func (s *bitset) delete(item int) {
+	if s.has(item) {
+		s.words[item/64] &^= 1 << (item % 64)
+		s.size--
+	}
+}
+
+// count recalculates the size after the words were modified in bulk.
+func (s *bitset) count() {
+	s.size = 0
+	for _, word := range s.words {
+		s.size += bits.OnesCount64(word)
+	}
+}
+
+// Add includes the specified items (one or more) to the set. The underlying
+// Set s is modified. If passed nothing it silently returns.
+func (s *bitset) Add(items ...int) Set[int] {
+	for _, item := range items {
+		s.insert(item)
+	}
+
+	return s
+}
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before.
+func (s *bitset) Insert(item int) bool { return s.insert(item) }
+
+// Remove deletes the specified items from the set. The underlying Set s is
+// modified. If passed nothing it silently returns.
+func (s *bitset) Remove(items ...int) Set[int] {
+	for _, item := range items {
+		s.delete(item)
+	}
+
+	return s
+}
+
+// AddSlice includes the items of the slice to the set.
+func (s *bitset) AddSlice(items []int) Set[int] { return s.Add(items...) }
+
+// AddSliceCount includes the items of the slice to the set and returns the
+// number of the new ones.
+func (s *bitset) AddSliceCount(items []int) int { return insertCount[int](s, items) }
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set.
+func (s *bitset) RemoveChanged(items ...int) bool { return removeChanged[int](s, items) }
+
+// MergeChanged merges t into s and reports whether any item was added.
+func (s *bitset) MergeChanged(t Set[int]) bool { return mergeChanged[int](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *bitset) RemoveSlice(items []int) Set[int] { return s.Remove(items...) }
+
+// Pop deletes and returns the smallest item of the set. If set is empty, false
+// is returned.
+func (s *bitset) Pop() (int, bool) {
+	for w, word := range s.words {
+		if word != 0 {
+			item := w*64 + bits.TrailingZeros64(word)
+			s.words[w] &= word - 1 // clears the lowest bit
+			s.size--
+			return item, true
+		}
+	}
+
+	return 0, false
+}
+
+// PopN deletes and returns up to n items from the set. The underlying Set s is
+// modified. If set has less than n items, all of them are returned.
+func (s *bitset) PopN(n int) []int { return popN(n, s.size, s.Pop) }
+
+// Has looks for the existence of items passed. It returns false if nothing is
+// passed. For multiple items it returns true only if all of the items exist.
+func (s *bitset) Has(items ...int) bool {
+	if len(items) == 0 {
+		return false
+	}
+
+	for _, item := range items {
+		if !s.has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAny reports whether at least one of the passed items exists.
+func (s *bitset) HasAny(items ...int) bool {
+	for _, item := range items {
+		if s.has(item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *bitset) Size() int     { return s.size }
+func (s *bitset) Len() int      { return s.size }
+func (s *bitset) Clear()        { clear(s.words); s.size = 0 }
+func (s *bitset) IsEmpty() bool { return s.size == 0 }
+
+// IsEqual test whether s and t are the same in size and have the same items.
+// Two bitsets are compared word by word.
+func (s *bitset) IsEqual(t Set[int]) bool {
+	if b, ok := t.(*bitset); ok {
+		if s.size != b.size {
+			return false
+		}
+		for w := range min(len(s.words), len(b.words)) {
+			if s.words[w] != b.words[w] {
+				return false
+			}
+		}
+		return true // the tails are zero, as the sizes are equal
+	}
+
+	if conv, ok := t.(lockedSet[int]); ok {
+		conv.RLock()
+		defer conv.RUnlock()
+		t = conv.unlocked()
+	}
+
+	return s.size == t.Size() && t.Each(s.has)
+}
+
+// IsSubset tests whether t is a subset of s.
+func (s *bitset) IsSubset(t Set[int]) bool {
+	if t.Size() > s.size {
+		return false
+	}
+
+	if b, ok := t.(*bitset); ok {
+		for w, word := range b.words {
+			var own uint64
+			if w < len(s.words) {
+				own = s.words[w]
+			}
+			if word&^own != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	return t.Each(s.has)
+}
+
+// IsSuperset tests whether t is a superset of s.
+func (s *bitset) IsSuperset(t Set[int]) bool { return t.IsSubset(s) }
+
+// Each traverses the items in the Set in ascending order, calling the provided
+// function for each set member. Traversal will continue until all items in the
+// Set have been visited, or if the closure returns false.
+func (s *bitset) Each(f func(item int) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set in ascending order.
+func (s *bitset) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for w, word := range s.words {
+			for ; word != 0; word &= word - 1 {
+				if !yield(w*64 + bits.TrailingZeros64(word)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Copy returns a new Set with a copy of s.
+func (s *bitset) Copy() Set[int] {
+	return &bitset{words: append([]uint64(nil), s.words...), size: s.size}
+}
+
+// Filter returns a new Set with the items of s satisfying the predicate.
+func (s *bitset) Filter(f func(item int) bool) Set[int] {
+	u := &bitset{words: make([]uint64, len(s.words))}
+	for item := range s.All() {
+		if f(item) {
+			u.insert(item)
+		}
+	}
+	return u
+}
+
+// String returns a string representation of s
+func (s *bitset) String() string { return stringSet[int](s) }
+
+// List returns a slice of all items in ascending order.
+func (s *bitset) List() []int {
+	list := make([]int, 0, s.size)
+	for item := range s.All() {
+		list = append(list, item)
+	}
+
+	return list
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set. Another bitset is merged word by word.
+func (s *bitset) Merge(t Set[int]) Set[int] {
+	b, ok := t.(*bitset)
+	if !ok {
+		t.Each(func(item int) bool {
+			s.insert(item)
+			return true
+		})
+		return s
+	}
+
+	if len(b.words) > len(s.words) {
+		s.words = append(s.words, make([]uint64, len(b.words)-len(s.words))...)
+	}
+	for w, word := range b.words {
+		s.words[w] |= word
+	}
+	s.count()
+
+	return s
+}
+
+// Separate removes the items of t from s. The underlying Set s is modified.
+// Another bitset is separated word by word.
+func (s *bitset) Separate(t Set[int]) Set[int] {
+	b, ok := t.(*bitset)
+	if !ok {
+		return s.Remove(t.List()...)
+	}
+
+	for w := range min(len(s.words), len(b.words)) {
+		s.words[w] &^= b.words[w]
+	}
+	s.count()
+
+	return s
+}
+
+// RemoveIf deletes every item of s satisfying the predicate. The underlying
+// Set s is modified.
+func (s *bitset) RemoveIf(f func(item int) bool) Set[int] {
+	for item := range s.All() {
+		if f(item) {
+			s.delete(item) // the iterator has read the word already
+		}
+	}
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *bitset) Retain(f func(item int) bool) Set[int] { return s.RemoveIf(not(f)) }
+
+// asBitsets returns the passed sets as bitsets, if all of them are.
+func asBitsets[T any](set1, set2 Set[T], sets ...Set[T]) ([]*bitset, bool) {
+	if _, ok := any(set1).(*bitset); !ok {
+		return nil, false // the common case, checked without allocating
+	}
+
+	bs := make([]*bitset, 0, len(sets)+2)
+	for _, s := range append([]Set[T]{set1, set2}, sets...) {
+		b, ok := any(s).(*bitset)
+		if !ok {
+			return nil, false
+		}
+		bs = append(bs, b)
+	}
+
+	return bs, true
+}
+
+// bitsetUnion returns a new bitset with the items of all the bitsets, ORing
+// their words.
+func bitsetUnion(bs []*bitset) *bitset {
+	n := 0
+	for _, b := range bs {
+		n = max(n, len(b.words))
+	}
+
+	u := &bitset{words: make([]uint64, n)}
+	for _, b := range bs {
+		for w, word := range b.words {
+			u.words[w] |= word
+		}
+	}
+	u.count()
+
+	return u
+}
+
+// bitsetIntersect returns a new bitset with the items common to all the
+// bitsets, ANDing their words.
+func bitsetIntersect(bs []*bitset) *bitset {
+	n := len(bs[0].words)
+	for _, b := range bs[1:] {
+		n = min(n, len(b.words))
+	}
+
+	u := &bitset{words: append([]uint64(nil), bs[0].words[:n]...)}
+	for _, b := range bs[1:] {
+		for w := range u.words {
+			u.words[w] &= b.words[w]
+		}
+	}
+	u.count()
+
+	return u
+}
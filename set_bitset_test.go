@@ -0,0 +1,146 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBitset(t *testing.T) {
+	s := NewBitset(10)
+	s.Add(3, 1, 200, 3)
+
+	if s.Size() != 3 || !s.Has(1, 3, 200) || s.Has(2) || s.Has(-1) || s.Has(1000) {
+		t.Error("Add: expected the items 1, 3 and 200, got", s)
+	}
+	if !reflect.DeepEqual(s.List(), []int{1, 3, 200}) {
+		t.Error("List: expected the items in ascending order, got", s.List())
+	}
+	if !s.IsEqual(newNonTS(1, 3, 200)) || !newTS(1, 3, 200).IsEqual(s) {
+		t.Error("IsEqual: bitset should be equal to a map-based set with the same items")
+	}
+
+	if s.Insert(3) || !s.Insert(64) {
+		t.Error("Insert: should report whether the item is new")
+	}
+	s.Remove(3, 500)
+	if item, ok := s.Pop(); !ok || item != 1 {
+		t.Error("Pop: expected the smallest item, got", item)
+	}
+	if !s.IsEqual(NewBitset(0).Add(64, 200)) {
+		t.Error("Remove: expected the items 64 and 200, got", s)
+	}
+
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Error("Clear: expected an empty set, got", s)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Add: negative item should panic")
+			}
+		}()
+		s.Add(-1)
+	}()
+}
+
+func TestBitset_wordwise(t *testing.T) {
+	a := NewBitset(100).Add(1, 64, 65, 130)
+	b := NewBitset(10).Add(1, 2, 65, 300)
+
+	if !a.Copy().Merge(b).IsEqual(newNonTS(1, 2, 64, 65, 130, 300)) {
+		t.Error("Merge: unexpected items", a.Copy().Merge(b))
+	}
+	if !a.Copy().Separate(b).IsEqual(newNonTS(64, 130)) {
+		t.Error("Separate: unexpected items", a.Copy().Separate(b))
+	}
+	if !a.IsSubset(NewBitset(0).Add(1, 130)) || a.IsSubset(b) {
+		t.Error("IsSubset: unexpected result for bitsets")
+	}
+	if !NewBitset(1000).Add(1).IsEqual(NewBitset(0).Add(1)) {
+		t.Error("IsEqual: bitsets of different capacity should be equal")
+	}
+
+	odd := a.Filter(func(item int) bool { return item%2 == 1 })
+	if !odd.IsEqual(newNonTS(1, 65)) {
+		t.Error("Filter: expected the odd items, got", odd)
+	}
+	a.RemoveIf(func(item int) bool { return item > 64 })
+	if !a.IsEqual(newNonTS(1, 64)) {
+		t.Error("RemoveIf: unexpected items", a)
+	}
+}
+
+const benchmarkDomain = 10000
+
+func benchmarkDense(b *testing.B, newSet func() Set[int]) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := newSet()
+		for item := 0; item <= benchmarkDomain; item++ {
+			s.Insert(item)
+		}
+		for item := 0; item <= benchmarkDomain; item++ {
+			s.Has(item)
+		}
+	}
+}
+
+func BenchmarkDenseBitset(b *testing.B) {
+	benchmarkDense(b, func() Set[int] { return NewBitset(benchmarkDomain) })
+}
+
+func BenchmarkDenseMap(b *testing.B) {
+	benchmarkDense(b, func() Set[int] { return newNonTS[int]() })
+}
+
+func Test_bitsetFastPath(t *testing.T) {
+	a := NewBitset(0).Add(1, 2, 64, 300)
+	b := NewBitset(1000).Add(2, 64, 65)
+	c := NewBitset(64).Add(2, 64, 300)
+
+	u := Union(a, b, c)
+	if _, ok := u.(*bitset); !ok || !u.IsEqual(newNonTS(1, 2, 64, 65, 300)) {
+		t.Error("Union: expected a bitset with all the items, got", u)
+	}
+	i := Intersection(a, b, c)
+	if _, ok := i.(*bitset); !ok || !i.IsEqual(newNonTS(2, 64)) {
+		t.Error("Intersection: expected a bitset with the common items, got", i)
+	}
+
+	// mixed kinds take the generic path
+	if !Union(a, newNonTS(7)).IsEqual(newNonTS(1, 2, 7, 64, 300)) {
+		t.Error("Union: unexpected result for a bitset and a map-based set")
+	}
+	if !Intersection(newNonTS(2, 7), a).IsEqual(newNonTS(2)) {
+		t.Error("Intersection: unexpected result for a map-based set and a bitset")
+	}
+}
+
+func benchmarkDenseAlgebra(b *testing.B, newSet func() Set[int]) {
+	x, y := newSet(), newSet()
+	for item := 0; item <= benchmarkDomain; item++ {
+		if item%2 == 0 {
+			x.Insert(item)
+		}
+		if item%3 == 0 {
+			y.Insert(item)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Union(x, y)
+		Intersection(x, y)
+	}
+}
+
+func BenchmarkDenseAlgebraBitset(b *testing.B) {
+	benchmarkDenseAlgebra(b, func() Set[int] { return NewBitset(benchmarkDomain) })
+}
+
+func BenchmarkDenseAlgebraMap(b *testing.B) {
+	benchmarkDenseAlgebra(b, func() Set[int] { return newNonTS[int]() })
+}
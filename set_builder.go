@@ -0,0 +1,70 @@
+package set
+
+// Builder collects items from various sources and builds a Set of them. It
+// isn't threadsafe and doesn't lock anything until the set is built, which
+// makes it cheap to fill with many items. The zero value is ready to use.
+//
+// A Builder is reusable: Build and BuildNonTS hand the collected items over to
+// the new set and leave the builder empty.
+type Builder[T comparable] struct {
+	m   map[T]struct{}
+	cap int
+}
+
+// NewBuilder creates a new empty Builder.
+func NewBuilder[T comparable]() *Builder[T] { return &Builder[T]{} }
+
+// NewBuilderCap creates a new empty Builder, which preallocates room for n
+// items, every time it's filled.
+func NewBuilderCap[T comparable](n int) *Builder[T] { return &Builder[T]{cap: n} }
+
+func (b *Builder[T]) init() {
+	if b.m == nil {
+		b.m = make(map[T]struct{}, b.cap)
+	}
+}
+
+// Add includes the specified items to the set being built.
+func (b *Builder[T]) Add(items ...T) *Builder[T] { return b.AddSlice(items) }
+
+// AddSlice includes the items of the slice to the set being built.
+func (b *Builder[T]) AddSlice(items []T) *Builder[T] {
+	b.init()
+	for _, item := range items {
+		b.m[item] = null{}
+	}
+
+	return b
+}
+
+// AddFrom includes the items of s to the set being built. Threadsafe sets are
+// read under a single read lock.
+func (b *Builder[T]) AddFrom(s Set[T]) *Builder[T] {
+	b.init()
+	s.Each(func(item T) bool {
+		b.m[item] = null{}
+		return true
+	})
+
+	return b
+}
+
+// Build returns a new threadsafe Set of the collected items and resets the
+// builder.
+func (b *Builder[T]) Build() Set[T] { return &setm[T]{set: b.take()} }
+
+// BuildNonTS returns a new non-threadsafe Set of the collected items and
+// resets the builder.
+func (b *Builder[T]) BuildNonTS() Set[T] {
+	s := b.take()
+	return &s
+}
+
+// take hands the collected items over and leaves the builder empty.
+func (b *Builder[T]) take() set[T] {
+	b.init()
+	m := b.m
+	b.m = nil
+
+	return set[T]{m}
+}
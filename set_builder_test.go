@@ -0,0 +1,33 @@
+package set
+
+import "testing"
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilderCap[int](8)
+	s := b.Add(1, 2).AddSlice([]int{2, 3}).AddFrom(newTS(3, 4)).Build()
+
+	if _, ok := s.(*setm[int]); !ok {
+		t.Errorf("Build: expected a threadsafe set, got %T", s)
+	}
+	if !s.IsEqual(newNonTS(1, 2, 3, 4)) {
+		t.Error("Build: expected the items of all sources, got", s)
+	}
+
+	u := b.Add(5).BuildNonTS()
+	if _, ok := u.(*set[int]); !ok {
+		t.Errorf("BuildNonTS: expected a non-threadsafe set, got %T", u)
+	}
+	if !u.IsEqual(newNonTS(5)) {
+		t.Error("BuildNonTS: reused builder should start empty, got", u)
+	}
+
+	u.Add(6)
+	if s.Has(6) {
+		t.Error("Build: built sets should not share their items")
+	}
+
+	var zero Builder[string]
+	if e := zero.Build(); !e.IsEmpty() {
+		t.Error("Build: zero builder should build an empty set, got", e)
+	}
+}
@@ -0,0 +1,150 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+)
+
+// setChecked is a non-threadsafe set, which detects its modification during
+// iteration.
+type setChecked[T comparable] struct {
+	set[T]
+	mods uint64 // incremented by every mutation
+}
+
+var _ Set[int] = (*setChecked[int])(nil)
+
+// NewNonTSChecked creates and initializes a new non-threadsafe Set, which
+// panics if it's modified while being iterated, e.g. by calling Add from the
+// callback of Each. It's meant for debugging: the regular sets could silently
+// skip or repeat items instead.
+func NewNonTSChecked[T comparable](items ...T) Set[T] {
+	s := &setChecked[T]{set: set[T]{make(map[T]struct{}, len(items))}}
+	s.set.Add(items...)
+
+	return s
+}
+
+// Add includes the specified items (one or more) to the set. The underlying
+// Set s is modified. If passed nothing it silently returns.
+func (s *setChecked[T]) Add(items ...T) Set[T] {
+	s.mods++
+	s.set.Add(items...)
+	return s
+}
+
+// AddSlice includes the items of the slice to the set.
+func (s *setChecked[T]) AddSlice(items []T) Set[T] {
+	s.mods++
+	s.set.AddSlice(items)
+	return s
+}
+
+// RemoveSlice deletes the items of the slice from the set.
+// AddSliceCount includes the items of the slice to the set and returns the
+// number of the new ones.
+func (s *setChecked[T]) AddSliceCount(items []T) int { return insertCount[T](s, items) }
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set.
+func (s *setChecked[T]) RemoveChanged(items ...T) bool { return removeChanged[T](s, items) }
+
+// MergeChanged merges t into s and reports whether any item was added.
+func (s *setChecked[T]) MergeChanged(t Set[T]) bool { return mergeChanged[T](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setChecked[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before.
+func (s *setChecked[T]) Insert(item T) bool {
+	s.mods++
+	return s.set.Insert(item)
+}
+
+// Remove deletes the specified items from the set.  The underlying Set s is
+// modified. If passed nothing it silently returns.
+func (s *setChecked[T]) Remove(items ...T) Set[T] {
+	s.mods++
+	s.set.Remove(items...)
+	return s
+}
+
+// Pop  deletes and return an item from the set. The underlying Set s is
+// modified. If set is empty, false is returned.
+func (s *setChecked[T]) Pop() (T, bool) {
+	s.mods++
+	return s.set.Pop()
+}
+
+// PopN deletes and returns up to n items from the set.
+func (s *setChecked[T]) PopN(n int) []T {
+	s.mods++
+	return s.set.PopN(n)
+}
+
+// Clear removes all items from the set.
+func (s *setChecked[T]) Clear() {
+	s.mods++
+	s.set.Clear()
+}
+
+// ClearKeepCap removes all items from the set, keeping the memory of the map.
+func (s *setChecked[T]) ClearKeepCap() {
+	s.mods++
+	s.set.ClearKeepCap()
+}
+
+// Each traverses the items in the Set, calling the provided function for each
+// set member. Traversal will continue until all items in the Set have been
+// visited, or if the closure returns false. It panics if the closure modifies
+// the set.
+func (s *setChecked[T]) Each(f func(item T) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set. It panics if the set is
+// modified during the iteration.
+func (s *setChecked[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		mods := s.mods
+		for item := range s.set.All() {
+			next := yield(item)
+			if s.mods != mods {
+				panic(fmt.Sprintf("set: modified during iteration, while visiting %v", item))
+			}
+			if !next {
+				return
+			}
+		}
+	}
+}
+
+// Copy returns a new Set with a copy of s.
+func (s *setChecked[T]) Copy() Set[T] {
+	return &setChecked[T]{set: *s.set.Copy().(*set[T])}
+}
+
+// Filter returns a new Set with the items of s satisfying the predicate.
+func (s *setChecked[T]) Filter(f func(item T) bool) Set[T] {
+	return &setChecked[T]{set: *s.set.Filter(f).(*set[T])}
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set.
+func (s *setChecked[T]) Merge(t Set[T]) Set[T] {
+	s.mods++
+	s.set.Merge(t)
+	return s
+}
+
+// RemoveIf deletes every item of s satisfying the predicate.
+func (s *setChecked[T]) RemoveIf(f func(item T) bool) Set[T] {
+	s.mods++
+	s.set.RemoveIf(f)
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *setChecked[T]) Retain(f func(item T) bool) Set[T] { return s.RemoveIf(not(f)) }
+
+// Separate removes the set items containing in t from set s.
+func (s *setChecked[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
@@ -0,0 +1,35 @@
+package set
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetChecked_Each(t *testing.T) {
+	s := NewNonTSChecked(1, 2, 3)
+
+	// read-only traversal works as usual
+	sum := 0
+	s.Each(func(item int) bool {
+		sum += item
+		return true
+	})
+	if sum != 6 {
+		t.Error("Each: expected the sum of 6, got", sum)
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Each: modification during iteration should panic")
+		}
+		if msg, ok := r.(string); !ok || !strings.Contains(msg, "modified during iteration") {
+			t.Error("Each: unexpected panic:", r)
+		}
+	}()
+
+	s.Each(func(item int) bool {
+		s.Add(item + 10)
+		return true
+	})
+}
@@ -0,0 +1,252 @@
+package set
+
+import (
+	"iter"
+	"slices"
+)
+
+// FairSet is a Set which pops its items in a stable rotating order: every pop
+// returns the item which has been waiting in the set for the longest time, so
+// an item that is popped and added back is returned only after all the others.
+// This makes it suitable for round-robin scheduling, no item is starved.
+type FairSet[T comparable] interface {
+	Set[T]
+	// PopFair deletes and returns the item under the cursor, which is the
+	// oldest item of the set. If set is empty, false is returned.
+	PopFair() (T, bool)
+}
+
+// UnsafeLister is implemented by sets, which keep their items contiguously in
+// order, so they can be listed without copying.
+type UnsafeLister[T any] interface {
+	// ListUnsafe returns the items of the set without copying them. The
+	// returned slice is shared with the set: it must not be modified, and is
+	// valid only until the next modification of the set.
+	ListUnsafe() []T
+}
+
+// setOrdered is a non-threadsafe set which remembers the order its items were
+// added in.
+type setOrdered[T comparable] struct {
+	set[T]
+	order []T // items before head were already popped
+	head  int // cursor of the next item to pop
+}
+
+var _ interface {
+	FairSet[int]
+	UnsafeLister[int]
+} = (*setOrdered[int])(nil)
+
+// NewOrdered creates and initializes a new non-threadsafe Set, which remembers
+// the order its items were first added in: List, Each and All yield them in
+// that order, and Pop removes the oldest item, like a FIFO queue.
+func NewOrdered[T comparable](items ...T) Set[T] { return NewFair(items...) }
+
+// NewFair creates and initializes a new non-threadsafe FairSet.
+func NewFair[T comparable](items ...T) FairSet[T] {
+	s := &setOrdered[T]{set: set[T]{make(map[T]struct{})}}
+	s.Add(items...)
+
+	return s
+}
+
+// Add includes the specified items (one or more) to the set. The underlying
+// Set s is modified. If passed nothing it silently returns.
+func (s *setOrdered[T]) Add(items ...T) Set[T] {
+	s.lazyInit()
+	for _, item := range items {
+		if _, ok := s.m[item]; ok {
+			continue
+		}
+		s.m[item] = null{}
+		s.order = append(s.order, item)
+	}
+
+	return s
+}
+
+// Remove deletes the specified items from the set.  The underlying Set s is
+// modified. If passed nothing it silently returns. Every item is looked up in
+// the order with a linear scan, so it takes O(n) time per item, while RemoveIf
+// removes any number of items in a single pass.
+func (s *setOrdered[T]) Remove(items ...T) Set[T] {
+	for _, item := range items {
+		if _, ok := s.m[item]; !ok {
+			continue
+		}
+		delete(s.m, item)
+		for i := s.head; i < len(s.order); i++ {
+			if s.order[i] == item {
+				copy(s.order[i:], s.order[i+1:])
+				clear(s.order[len(s.order)-1:]) // don't hold the removed item
+				s.order = s.order[:len(s.order)-1]
+				break
+			}
+		}
+	}
+
+	return s
+}
+
+// AddSlice appends the items of the slice to the end of the order. Room for
+// all of them is made first.
+func (s *setOrdered[T]) AddSlice(items []T) Set[T] {
+	s.set.grow(len(items))
+	s.order = slices.Grow(s.order, len(items))
+
+	return s.Add(items...)
+}
+
+// AddSliceCount appends the new items of the slice to the end of the order and
+// returns their number.
+func (s *setOrdered[T]) AddSliceCount(items []T) int { return insertCount[T](s, items) }
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set.
+func (s *setOrdered[T]) RemoveChanged(items ...T) bool { return removeChanged[T](s, items) }
+
+// MergeChanged merges t into s and reports whether any item was added.
+func (s *setOrdered[T]) MergeChanged(t Set[T]) bool { return mergeChanged[T](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setOrdered[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// Insert appends the item to the end of the order and reports whether it
+// wasn't in the set before. An existing item keeps its position.
+func (s *setOrdered[T]) Insert(item T) bool {
+	if _, ok := s.m[item]; ok {
+		return false
+	}
+	s.Add(item)
+
+	return true
+}
+
+// Pop is the same as PopFair.
+func (s *setOrdered[T]) Pop() (T, bool) { return s.PopFair() }
+
+// PopN deletes and returns up to n oldest items from the set, in insertion
+// order.
+func (s *setOrdered[T]) PopN(n int) []T { return popN(n, len(s.m), s.PopFair) }
+
+// PopFair deletes and returns the item under the cursor, which is the oldest
+// item of the set. If set is empty, false is returned.
+func (s *setOrdered[T]) PopFair() (T, bool) {
+	var t T
+	if s.head == len(s.order) {
+		return t, false
+	}
+
+	item := s.order[s.head]
+	s.order[s.head] = t // don't hold the popped item
+	s.head++
+	delete(s.m, item)
+
+	// compact the order once most of it is already popped
+	if s.head > len(s.order)/2 {
+		n := copy(s.order, s.order[s.head:])
+		clear(s.order[n:]) // don't hold the moved items twice
+		s.order = s.order[:n]
+		s.head = 0
+	}
+
+	return item, true
+}
+
+// Clear removes all items from the set.
+func (s *setOrdered[T]) Clear() {
+	s.m = make(map[T]struct{})
+	s.order = nil
+	s.head = 0
+}
+
+// ClearKeepCap removes all items from the set, keeping the memory of both the
+// map and the order.
+func (s *setOrdered[T]) ClearKeepCap() {
+	clear(s.m)
+	clear(s.order) // don't keep the removed items reachable
+	s.order = s.order[:0]
+	s.head = 0
+}
+
+// Each traverses the items in the Set in the order they were added, calling
+// the provided function for each set member. Traversal will continue until
+// all items in the Set have been visited, or if the closure returns false.
+func (s *setOrdered[T]) Each(f func(item T) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set in the order they were
+// added.
+func (s *setOrdered[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range s.order[s.head:] {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Copy returns a new Set with a copy of s.
+func (s *setOrdered[T]) Copy() Set[T] { return NewFair(s.order[s.head:]...) }
+
+// Filter returns a new Set with the items of s satisfying the predicate, in
+// the order they were added.
+func (s *setOrdered[T]) Filter(f func(item T) bool) Set[T] {
+	u := NewFair[T]()
+	for _, item := range s.order[s.head:] {
+		if f(item) {
+			u.Add(item)
+		}
+	}
+	return u
+}
+
+// String returns a string representation of s
+func (s *setOrdered[T]) String() string { return stringSet[T](s) }
+
+// List returns a slice of all items in the order they were added.
+func (s *setOrdered[T]) List() []T {
+	return append(make([]T, 0, len(s.m)), s.order[s.head:]...)
+}
+
+// ListUnsafe returns the items in the order they were added, without copying
+// them.
+func (s *setOrdered[T]) ListUnsafe() []T {
+	// capped, so appending to the result never overwrites the set
+	return s.order[s.head:len(s.order):len(s.order)]
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set.
+func (s *setOrdered[T]) Merge(t Set[T]) Set[T] {
+	t.Each(func(item T) bool {
+		s.Add(item)
+		return true
+	})
+
+	return s
+}
+
+// RemoveIf deletes every item of s satisfying the predicate. The order of the
+// remaining items is kept.
+func (s *setOrdered[T]) RemoveIf(f func(item T) bool) Set[T] {
+	kept := s.order[:s.head]
+	for _, item := range s.order[s.head:] {
+		if f(item) {
+			delete(s.m, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	clear(s.order[len(kept):]) // don't hold the removed items
+	s.order = kept
+
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *setOrdered[T]) Retain(f func(item T) bool) Set[T] { return s.RemoveIf(not(f)) }
+
+// Separate removes the set items containing in t from set s.
+func (s *setOrdered[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
@@ -0,0 +1,125 @@
+package set
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSetFair_PopFair(t *testing.T) {
+	s := NewFair("a", "b", "c", "d")
+
+	for _, want := range []string{"a", "b"} {
+		if item, ok := s.PopFair(); !ok || item != want {
+			t.Errorf("PopFair: expected %q, got %q", want, item)
+		}
+	}
+
+	// popped item added back waits behind the others
+	s.Add("a")
+
+	for _, want := range []string{"c", "d", "a"} {
+		if item, ok := s.PopFair(); !ok || item != want {
+			t.Errorf("PopFair: expected %q, got %q", want, item)
+		}
+	}
+
+	if _, ok := s.PopFair(); ok {
+		t.Error("PopFair: should return false because set is empty")
+	}
+
+	s.Add("a", "b", "c", "d")
+	s.PopFair()
+	s.PopFair()
+	s.PopFair() // compacts the order, moving "d" to the front
+	o := s.(*setOrdered[string])
+	if tail := o.order[len(o.order):cap(o.order)]; slices.ContainsFunc(tail, func(item string) bool { return item != "" }) {
+		t.Error("PopFair: the compacted order should not hold the moved items, got", tail)
+	}
+}
+
+func TestSetFair_PopFair_distinct(t *testing.T) {
+	s := NewFair(1, 2, 3, 4)
+
+	seen := newNonTS[int]()
+	for i := 0; i < 4; i++ {
+		item, ok := s.PopFair()
+		if !ok {
+			t.Fatal("PopFair: set shouldn't be empty yet")
+		}
+		if seen.Has(item) {
+			t.Error("PopFair: item returned twice:", item)
+		}
+		seen.Add(item)
+	}
+
+	if !s.IsEmpty() || seen.Size() != 4 {
+		t.Error("PopFair: every item should be popped exactly once")
+	}
+}
+
+func TestSetFair_ListUnsafe(t *testing.T) {
+	s := NewFair("a", "b", "c")
+	s.Pop()
+
+	list := s.(UnsafeLister[string]).ListUnsafe()
+	if len(list) != 2 || list[0] != "b" || list[1] != "c" {
+		t.Error("ListUnsafe: expected items in order they were added, got", list)
+	}
+
+	_ = append(list, "d")
+	if s.Size() != 2 || s.List()[1] != "c" {
+		t.Error("ListUnsafe: appending to the result should not modify the set, got", s)
+	}
+}
+
+func benchmarkFairList(b *testing.B, list func(FairSet[int]) []int) {
+	s := NewFair[int]()
+	for i := 0; i < 1000; i++ {
+		s.Add(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list(s)
+	}
+}
+
+func BenchmarkFairList(b *testing.B) {
+	benchmarkFairList(b, func(s FairSet[int]) []int { return s.List() })
+}
+
+func BenchmarkFairListUnsafe(b *testing.B) {
+	benchmarkFairList(b, func(s FairSet[int]) []int { return s.(UnsafeLister[int]).ListUnsafe() })
+}
+
+func TestSetOrdered(t *testing.T) {
+	s := NewOrdered("c", "a", "b")
+	s.Add("c") // already added, keeps its position
+	s.Remove("a")
+
+	if got := s.List(); !reflect.DeepEqual(got, []string{"c", "b"}) {
+		t.Error("List: expected items in order they were added, got", got)
+	}
+
+	s.Add("a")
+	var visited []string
+	s.Each(func(item string) bool {
+		visited = append(visited, item)
+		return true
+	})
+	if !reflect.DeepEqual(visited, []string{"c", "b", "a"}) {
+		t.Error("Each: expected items in order they were added, got", visited)
+	}
+
+	if item, _ := s.Pop(); item != "c" {
+		t.Error("Pop: expected the oldest item, got", item)
+	}
+
+	o := s.(*setOrdered[string])
+	s.Remove("b")
+	if tail := o.order[len(o.order) : len(o.order)+1]; tail[0] != "" {
+		t.Error("Remove: the removed item should not be held by the order, got", tail)
+	}
+}
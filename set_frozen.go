@@ -0,0 +1,45 @@
+package set
+
+// frozen is a read-only view of a set.
+type frozen[T any] struct {
+	Set[T]
+}
+
+// Freeze returns a read-only view of s. The query methods of the view work
+// as usual and reflect later changes of s, while its methods modifying the set
+// (Add, AddSlice, AddSliceCount, Insert, Remove, RemoveSlice, RemoveChanged,
+// Pop, PopN, Clear, Merge, MergeChanged, Separate, RemoveIf and Retain) panic
+// with a "frozen set" message. Copy and Filter return regular, mutable sets.
+func Freeze[T any](s Set[T]) Set[T] {
+	if f, ok := s.(*frozen[T]); ok {
+		return f
+	}
+
+	return &frozen[T]{Set: s}
+}
+
+func frozenPanic(method string) {
+	panic("set: " + method + " called on a frozen set")
+}
+
+func (s *frozen[T]) Add(items ...T) Set[T]          { frozenPanic("Add"); return s }
+func (s *frozen[T]) Insert(item T) bool             { frozenPanic("Insert"); return false }
+func (s *frozen[T]) Remove(items ...T) Set[T]       { frozenPanic("Remove"); return s }
+func (s *frozen[T]) AddSlice(items []T) Set[T]      { frozenPanic("AddSlice"); return s }
+func (s *frozen[T]) RemoveSlice(items []T) Set[T]   { frozenPanic("RemoveSlice"); return s }
+func (s *frozen[T]) AddSliceCount(items []T) int    { frozenPanic("AddSliceCount"); return 0 }
+func (s *frozen[T]) RemoveChanged(items ...T) bool  { frozenPanic("RemoveChanged"); return false }
+func (s *frozen[T]) MergeChanged(t Set[T]) bool     { frozenPanic("MergeChanged"); return false }
+func (s *frozen[T]) PopN(n int) []T                 { frozenPanic("PopN"); return nil }
+func (s *frozen[T]) Clear()                         { frozenPanic("Clear") }
+func (s *frozen[T]) Merge(t Set[T]) Set[T]          { frozenPanic("Merge"); return s }
+func (s *frozen[T]) Separate(t Set[T]) Set[T]       { frozenPanic("Separate"); return s }
+func (s *frozen[T]) RemoveIf(f func(T) bool) Set[T] { frozenPanic("RemoveIf"); return s }
+func (s *frozen[T]) Retain(f func(T) bool) Set[T]   { frozenPanic("Retain"); return s }
+
+func (s *frozen[T]) Pop() (T, bool) {
+	frozenPanic("Pop")
+
+	var t T
+	return t, false
+}
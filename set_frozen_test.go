@@ -0,0 +1,58 @@
+package set
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Freeze(t *testing.T) {
+	s := newTS(1, 2, 3)
+	f := Freeze(s)
+
+	for name, mutate := range map[string]func(){
+		"Add":         func() { f.Add(4) },
+		"AddSlice":    func() { f.AddSlice([]int{4}) },
+		"Insert":      func() { f.Insert(4) },
+		"RemoveSlice": func() { f.RemoveSlice([]int{1}) },
+		"Remove":      func() { f.Remove(1) },
+		"Pop":         func() { f.Pop() },
+		"PopN":        func() { f.PopN(1) },
+		"Clear":       func() { f.Clear() },
+		"Merge":       func() { f.Merge(newNonTS(4)) },
+		"Separate":    func() { f.Separate(newNonTS(1)) },
+		"RemoveIf":    func() { f.RemoveIf(func(int) bool { return true }) },
+		"Retain":      func() { f.Retain(func(int) bool { return false }) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(r.(string), "frozen set") {
+					t.Errorf("Freeze: %s should panic with a frozen set message, got %v", name, r)
+				}
+			}()
+			mutate()
+		}()
+	}
+
+	if s.Size() != 3 || !s.Has(1, 2, 3) {
+		t.Error("Freeze: underlying set should not be modified, got", s)
+	}
+
+	if f.Size() != 3 || !f.Has(1, 2) || f.IsEmpty() || !f.IsEqual(s) || len(f.List()) != 3 {
+		t.Error("Freeze: query methods should work on the frozen set, got", f)
+	}
+
+	s.Add(4)
+	if !f.Has(4) {
+		t.Error("Freeze: frozen set should reflect the changes of the underlying set")
+	}
+
+	u := f.Copy()
+	u.Add(5)
+	if !u.Has(5) || f.Has(5) {
+		t.Error("Freeze: copy of a frozen set should be mutable and independent")
+	}
+
+	if Freeze(f) != f {
+		t.Error("Freeze: freezing a frozen set should return it as is")
+	}
+}
@@ -0,0 +1,159 @@
+package set
+
+import "golang.org/x/exp/constraints"
+
+// Map returns a new non-threadsafe set of the results of f applied to every
+// item of s. Items mapped to the same result are collapsed into one.
+func Map[T, U comparable](s Set[T], f func(T) U) Set[U] {
+	u := newNonTS[U]()
+	s.Each(func(item T) bool {
+		u.Add(f(item))
+		return true
+	})
+
+	return u
+}
+
+// DeepCopy returns a new set of the same kind as s, with clone applied to
+// every item. Unlike Copy, which shares the items, it lets pointer items be
+// copied too. Threadsafe sets are copied under a single read lock.
+func DeepCopy[T comparable](s Set[T], clone func(T) T) Set[T] {
+	items := make([]T, 0, s.Size())
+	s.Each(func(item T) bool {
+		items = append(items, clone(item))
+		return true
+	})
+
+	return emptyLike(s).AddSlice(items)
+}
+
+// Reduce folds every item of s into an accumulator, starting with init. The
+// items are visited in an unspecified order, so f must be commutative and
+// associative for the result to be deterministic. Threadsafe sets are folded
+// under a single read lock.
+func Reduce[T any, A any](s Set[T], init A, f func(A, T) A) A {
+	acc := init
+	s.Each(func(item T) bool {
+		acc = f(acc, item)
+		return true
+	})
+
+	return acc
+}
+
+// Count returns the number of items of s satisfying the predicate, without
+// allocating. Threadsafe sets are counted under a single read lock.
+func Count[T any](s Set[T], pred func(T) bool) int {
+	n := 0
+	s.Each(func(item T) bool {
+		if pred(item) {
+			n++
+		}
+		return true
+	})
+
+	return n
+}
+
+// Partition splits s into the set of items satisfying the predicate and the
+// set of the rest, in a single pass. Both sets are of the same kind as s.
+// Threadsafe sets are scanned under a single read lock.
+func Partition[T comparable](s Set[T], pred func(T) bool) (matched, rest Set[T]) {
+	matched, rest = emptyLike(s), emptyLike(s)
+	s.Each(func(item T) bool {
+		if pred(item) {
+			matched.Add(item)
+		} else {
+			rest.Add(item)
+		}
+		return true
+	})
+
+	return matched, rest
+}
+
+// Chunk splits the items of s into slices of at most size items each, the
+// last one possibly shorter. Every item appears in exactly one chunk, in an
+// unspecified order. If size <= 0, all the items are returned in a single
+// chunk. An empty set gives no chunks.
+func Chunk[T comparable](s Set[T], size int) [][]T {
+	items := s.List()
+	if size <= 0 {
+		size = len(items)
+	}
+
+	var chunks [][]T
+	for len(items) > 0 {
+		n := min(size, len(items))
+		chunks = append(chunks, items[:n:n])
+		items = items[n:]
+	}
+
+	return chunks
+}
+
+// Any reports whether at least one item of s satisfies the predicate. It
+// stops at the first matching item, and returns false for an empty set.
+func Any[T any](s Set[T], pred func(T) bool) bool { return !s.Each(not(pred)) }
+
+// All reports whether every item of s satisfies the predicate. It stops at the
+// first item not matching, and returns true for an empty set.
+func All[T any](s Set[T], pred func(T) bool) bool { return s.Each(pred) }
+
+// Min returns the smallest item of s, or false if s is empty. Threadsafe sets
+// are scanned under a single read lock.
+func Min[T constraints.Ordered](s Set[T]) (T, bool) {
+	return extreme(s, func(a, b T) bool { return a < b })
+}
+
+// Max returns the largest item of s, or false if s is empty. Threadsafe sets
+// are scanned under a single read lock.
+func Max[T constraints.Ordered](s Set[T]) (T, bool) {
+	return extreme(s, func(a, b T) bool { return a > b })
+}
+
+// extreme returns the item of s, which is better than any other item.
+func extreme[T any](s Set[T], better func(a, b T) bool) (T, bool) {
+	var (
+		result T
+		found  bool
+	)
+	s.Each(func(item T) bool {
+		if !found || better(item, result) {
+			result, found = item, true
+		}
+		return true
+	})
+
+	return result, found
+}
+
+// MinBy returns the item of s with the smallest value of f, or false if s is
+// empty. Threadsafe sets are scanned under a single read lock.
+func MinBy[T comparable](s Set[T], f func(T) float64) (T, bool) {
+	return extremeBy(s, f, func(a, b float64) bool { return a < b })
+}
+
+// MaxBy returns the item of s with the largest value of f, or false if s is
+// empty. Threadsafe sets are scanned under a single read lock.
+func MaxBy[T comparable](s Set[T], f func(T) float64) (T, bool) {
+	return extremeBy(s, f, func(a, b float64) bool { return a > b })
+}
+
+// extremeBy returns the item of s, whose value of f is better than the value
+// of any other item.
+func extremeBy[T comparable](s Set[T], f func(T) float64, better func(a, b float64) bool) (T, bool) {
+	var (
+		extreme T
+		value   float64
+		found   bool
+	)
+	s.Each(func(item T) bool {
+		if v := f(item); !found || better(v, value) {
+			extreme, value, found = item, v, true
+		}
+		return true
+	})
+
+	return extreme, found
+}
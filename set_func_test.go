@@ -0,0 +1,195 @@
+package set
+
+import "testing"
+
+func Test_Map(t *testing.T) {
+	type user struct {
+		id   int
+		name string
+	}
+
+	users := newTS(user{1, "fatih"}, user{2, "zeynep"}, user{3, "fatih"})
+	names := Map(users, func(u user) string { return u.name })
+
+	if names.Size() != 2 || !names.Has("fatih", "zeynep") {
+		t.Error("Map: expected two distinct names, got", names)
+	}
+}
+
+func Test_Reduce(t *testing.T) {
+	sum := Reduce(newTS(1, 2, 3, 4), 0, func(acc, item int) int { return acc + item })
+	if sum != 10 {
+		t.Error("Reduce: expected the sum of 10, got", sum)
+	}
+
+	length := Reduce(newNonTS("go", "set", "fatih"), 0, func(acc int, item string) int { return acc + len(item) })
+	if length != 10 {
+		t.Error("Reduce: expected the total length of 10, got", length)
+	}
+
+	if empty := Reduce(newNonTS[int](), 42, func(acc, item int) int { return acc + item }); empty != 42 {
+		t.Error("Reduce: empty set should return the initial value, got", empty)
+	}
+}
+
+func Test_MinByMaxBy(t *testing.T) {
+	s := newTS("gopher", "go", "set")
+	length := func(item string) float64 { return float64(len(item)) }
+
+	if item, ok := MinBy(s, length); !ok || item != "go" {
+		t.Error("MinBy: expected the shortest string, got", item)
+	}
+
+	if item, ok := MaxBy(s, length); !ok || item != "gopher" {
+		t.Error("MaxBy: expected the longest string, got", item)
+	}
+
+	if _, ok := MinBy(newNonTS[string](), length); ok {
+		t.Error("MinBy: empty set should return false")
+	}
+}
+
+func Test_Count(t *testing.T) {
+	numbers := newTS(1, 2, 3, 4, 5, 6)
+	if n := Count(numbers, func(item int) bool { return item%2 == 0 }); n != 3 {
+		t.Error("Count: expected three even numbers, got", n)
+	}
+
+	words := newNonTS("go", "set", "gopher", "generic")
+	if n := Count(words, func(item string) bool { return len(item) > 3 }); n != 2 {
+		t.Error("Count: expected two strings longer than three, got", n)
+	}
+
+	if n := Count(newNonTS[int](), func(int) bool { return true }); n != 0 {
+		t.Error("Count: empty set should count zero, got", n)
+	}
+}
+
+func Test_AnyAll(t *testing.T) {
+	even := func(item int) bool { return item%2 == 0 }
+
+	tests := []struct {
+		name     string
+		s        Set[int]
+		any, all bool
+	}{
+		{"empty", newNonTS[int](), false, true},
+		{"all match", newTS(2, 4, 6), true, true},
+		{"some match", newTS(1, 2, 3), true, false},
+		{"none match", newNonTS(1, 3, 5), false, false},
+	}
+
+	for _, tt := range tests {
+		if got := Any(tt.s, even); got != tt.any {
+			t.Errorf("Any: %s: expected %v, got %v", tt.name, tt.any, got)
+		}
+		if got := All(tt.s, even); got != tt.all {
+			t.Errorf("All: %s: expected %v, got %v", tt.name, tt.all, got)
+		}
+	}
+
+	visited := 0
+	Any(newNonTS(2, 4, 6), func(item int) bool {
+		visited++
+		return even(item)
+	})
+	if visited != 1 {
+		t.Error("Any: should stop at the first matching item, visited", visited)
+	}
+}
+
+func Test_MinMax(t *testing.T) {
+	if min, ok := Min(newTS(3, 1, 4, 1, 5)); !ok || min != 1 {
+		t.Error("Min: expected one, got", min)
+	}
+	if max, ok := Max(newTS(3, 1, 4, 1, 5)); !ok || max != 5 {
+		t.Error("Max: expected five, got", max)
+	}
+
+	if min, ok := Min(newNonTS("go")); !ok || min != "go" {
+		t.Error("Min: single item should be the smallest, got", min)
+	}
+	if max, ok := Max(newNonTS("go")); !ok || max != "go" {
+		t.Error("Max: single item should be the largest, got", max)
+	}
+
+	if _, ok := Min(newNonTS[int]()); ok {
+		t.Error("Min: empty set should return false")
+	}
+	if _, ok := Max(newTS[int]()); ok {
+		t.Error("Max: empty set should return false")
+	}
+}
+
+func Test_Partition(t *testing.T) {
+	s := newTS(1, 2, 3, 4, 5)
+
+	even, odd := Partition(s, func(item int) bool { return item%2 == 0 })
+	if !even.IsEqual(newNonTS(2, 4)) || !odd.IsEqual(newNonTS(1, 3, 5)) {
+		t.Errorf("Partition: expected even and odd items, got %v and %v", even, odd)
+	}
+	if !IsDisjoint(even, odd) {
+		t.Error("Partition: partitions should be disjoint")
+	}
+	if !Union(even, odd).IsEqual(s) {
+		t.Error("Partition: union of the partitions should be the source")
+	}
+
+	if _, ok := even.(*setm[int]); !ok {
+		t.Errorf("Partition: expected partitions of the source kind, got %T", even)
+	}
+}
+
+func Test_Chunk(t *testing.T) {
+	s := newTS[int]()
+	for i := 0; i < 10; i++ {
+		s.Add(i)
+	}
+
+	chunks := Chunk(s, 3)
+	if len(chunks) != 4 {
+		t.Fatal("Chunk: expected four chunks, got", len(chunks))
+	}
+
+	seen := newNonTS[int]()
+	total := 0
+	for i, chunk := range chunks {
+		if len(chunk) > 3 || (i < 3 && len(chunk) != 3) {
+			t.Errorf("Chunk: unexpected size of chunk %d: %d", i, len(chunk))
+		}
+		total += len(chunk)
+		seen.Add(chunk...)
+	}
+	if total != 10 || !seen.IsEqual(s) {
+		t.Error("Chunk: every item should appear exactly once, got", chunks)
+	}
+
+	if chunks := Chunk(s, 0); len(chunks) != 1 || len(chunks[0]) != 10 {
+		t.Error("Chunk: non-positive size should give a single chunk, got", chunks)
+	}
+	if chunks := Chunk(newNonTS[int](), 3); len(chunks) != 0 {
+		t.Error("Chunk: empty set should give no chunks, got", chunks)
+	}
+}
+
+func Test_DeepCopy(t *testing.T) {
+	one := 1
+	s := newTS(&one)
+
+	c := DeepCopy(s, func(item *int) *int {
+		clone := *item
+		return &clone
+	})
+	if c.Size() != 1 || c.Has(&one) {
+		t.Fatal("DeepCopy: expected a single cloned item, got", c)
+	}
+
+	item, _ := c.Pop()
+	*item = 2
+	if one != 1 {
+		t.Error("DeepCopy: changing a clone should not change the original item, got", one)
+	}
+	if _, ok := c.(*setm[*int]); !ok {
+		t.Errorf("DeepCopy: expected a copy of the source kind, got %T", c)
+	}
+}
@@ -0,0 +1,145 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+func gobEncodeItems[T any](items []T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(items); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func gobDecodeItems[T any](data []byte) ([]T, error) {
+	var items []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GobEncode encodes the set as a gob list of its items.
+func (s *set[T]) GobEncode() ([]byte, error) { return gobEncodeItems(s.List()) }
+
+// GobDecode replaces the items of the set with the items of a gob list.
+func (s *set[T]) GobDecode(data []byte) error {
+	items, err := gobDecodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.m = make(map[T]struct{}, len(items))
+	s.Add(items...)
+
+	return nil
+}
+
+// GobEncode encodes the set as a gob list of its items.
+func (s *setm[T]) GobEncode() ([]byte, error) { return gobEncodeItems(s.List()) }
+
+// GobDecode replaces the items of the set with the items of a gob list. A
+// zero value set is initialized by decoding.
+func (s *setm[T]) GobDecode(data []byte) error {
+	items, err := gobDecodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.clear()
+	s.add(items...)
+
+	return nil
+}
+
+// GobEncode encodes the set as a gob list of its items.
+func (s *setAny[T]) GobEncode() ([]byte, error) { return gobEncodeItems(s.List()) }
+
+// GobDecode replaces the items of the set with the items of a gob list.
+func (s *setAny[T]) GobDecode(data []byte) error {
+	items, err := gobDecodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
+
+// GobEncode encodes the set as a gob list of its items.
+func (s *setAnym[T]) GobEncode() ([]byte, error) { return gobEncodeItems(s.List()) }
+
+// GobDecode replaces the items of the set with the items of a gob list.
+func (s *setAnym[T]) GobDecode(data []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.setAny.GobDecode(data)
+}
+
+// GobEncode encodes the set as a gob list of its items, in the order they
+// were added.
+func (s *setOrdered[T]) GobEncode() ([]byte, error) { return gobEncodeItems(s.List()) }
+
+// GobDecode replaces the items of the set with the items of a gob list.
+func (s *setOrdered[T]) GobDecode(data []byte) error {
+	items, err := gobDecodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
+
+// GobDecode replaces the items of the set with the items of a gob list,
+// keeping only the most recent ones within the window.
+func (s *setWindowed[T]) GobDecode(data []byte) error {
+	items, err := gobDecodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
+
+// GobDecode replaces the items of the set with the normalized items of a gob
+// list.
+func (s *setNormalized) GobDecode(data []byte) error {
+	items, err := gobDecodeItems[string](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
+
+// GobDecode replaces the items of the set and the store with the items of a
+// gob list.
+func (s *setPersistent[T]) GobDecode(data []byte) error {
+	items, err := gobDecodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+)
+
+func Test_Gob(t *testing.T) {
+	testGob(t, New(3, 1, 2), NewNonTS[int]())
+	testGob(t, NewNonTS("b", "c", "a"), New[string]())
+	testGob(t, NewAny[hashInt](2, 1), NewAnyNonTS[hashInt]())
+
+	fair := NewFair("b", "c", "a")
+	decoded := NewFair[string]()
+	testGob[string](t, fair, decoded)
+	if l := decoded.List(); !reflect.DeepEqual(l, []string{"b", "c", "a"}) {
+		t.Error("GobDecode: ordered set should keep the order, got", l)
+	}
+
+	// a zero value threadsafe set is initialized by decoding
+	var s setm[int]
+	testGob[int](t, New(1, 2), &s)
+}
+
+func testGob[T comparable](t *testing.T, s, decoded Set[T]) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.IsEqual(s) {
+		t.Errorf("GobDecode: expected %v, got %v", s, decoded)
+	}
+}
@@ -1,9 +1,44 @@
 package set
 
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math"
+	"reflect"
+)
+
+// Hashable is implemented by items of the sets created by NewAny and
+// NewAnyNonTS. Items sharing the same hash are told apart either by their
+// Equal method, if they implement Equaler, or with the == operator. Items of a
+// type which is neither, e.g. a struct with a slice field, can't be told apart,
+// so comparing them panics; they must implement Equaler, or be put in a set
+// created by NewFunc with an explicit eq.
 type Hashable interface {
 	Hash() (uint64, error)
 }
 
+// FallibleSet is implemented by the sets created by NewAny and NewAnyNonTS,
+// whose methods panic if an item fails to hash. Its methods return the error
+// instead, so it can be handled.
+type FallibleSet[T any] interface {
+	Set[T]
+	AddErr(items ...T) error
+	HasErr(items ...T) (bool, error)
+}
+
+var (
+	_ FallibleSet[int] = (*setAny[int])(nil)
+	_ FallibleSet[int] = (*setAnym[int])(nil)
+)
+
+// Equaler is optionally implemented by Hashable items, which can't be
+// compared with the == operator.
+type Equaler[T any] interface {
+	Equal(T) bool
+}
+
 func mushHash(item Hashable) uint64 {
 	h, err := item.Hash()
 	if err != nil {
@@ -12,38 +47,187 @@ func mushHash(item Hashable) uint64 {
 	return h
 }
 
-type setAny[T Hashable] map[uint64]T
+// equalItems tells apart items sharing the same hash. Equal hashes don't make
+// items equal, so items which can't be compared with == panic with a hint,
+// instead of the runtime error.
+func equalItems[T any](a, b T) bool {
+	if eq, ok := any(a).(Equaler[T]); ok {
+		return eq.Equal(b)
+	}
+	if ta := reflect.TypeOf(a); ta != nil && !ta.Comparable() {
+		panic(fmt.Sprintf("set: items of type %v can't be compared with ==, implement Equaler or use NewFunc", ta))
+	}
+	return any(a) == any(b)
+}
+
+// setAny is the non-threadsafe set of items, which are hashed and compared by
+// the functions given to NewFunc, or else are Hashable. The zero value is an
+// empty set of Hashable items ready to use, its map is made on the first add.
+type setAny[T any] struct {
+	m    map[uint64][]T // items with colliding hashes share the bucket
+	size int
+
+	key func(T) uint64    // nil for Hashable items
+	eq  func(a, b T) bool // nil for Hashable items
+}
+
+func newAnyNonTS[T Hashable](items ...T) Set[T] {
+	return (&setAny[T]{m: make(map[uint64][]T)}).Add(items...)
+}
+
+// hash returns the hash of the item, by which it's bucketed.
+func (s *setAny[T]) hash(item T) uint64 {
+	if s.key != nil {
+		return s.key(item)
+	}
+	return mushHash(any(item).(Hashable))
+}
+
+// hashErr is like hash, but returns the error of Hash instead of panicking.
+func (s *setAny[T]) hashErr(item T) (uint64, error) {
+	if s.key != nil {
+		return s.key(item), nil
+	}
+	return any(item).(Hashable).Hash()
+}
+
+// equal reports whether the items, sharing a bucket, are the same.
+func (s *setAny[T]) equal(a, b T) bool {
+	if s.eq != nil {
+		return s.eq(a, b)
+	}
+	return equalItems(a, b)
+}
+
+// find returns the index of the item in the bucket of hash h, or -1.
+func (s *setAny[T]) find(h uint64, item T) int {
+	for i, candidate := range s.m[h] {
+		if s.equal(candidate, item) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lazyInit makes the map of a zero value set, so items can be added to it.
+func (s *setAny[T]) lazyInit() {
+	if s.m == nil {
+		s.m = make(map[uint64][]T)
+	}
+}
+
+func (s *setAny[T]) has(item T) bool { return s.find(s.hash(item), item) >= 0 }
 
-func newAnyNonTS[T Hashable](items ...T) Set[T] { return make(setAny[T]).Add(items...) }
+func (s *setAny[T]) removeAt(h uint64, i int) {
+	bucket := s.m[h]
+	if len(bucket) == 1 {
+		delete(s.m, h)
+	} else {
+		s.m[h] = append(bucket[:i:i], bucket[i+1:]...)
+	}
+	s.size--
+}
 
 // Add includes the specified items (one or more) to the set. The underlying
 // Set s is modified. If passed nothing it silently returns.
-func (s setAny[T]) Add(items ...T) Set[T] {
+func (s *setAny[T]) Add(items ...T) Set[T] {
 	for _, item := range items {
-		h, err := item.Hash()
+		s.insert(s.hash(item), item)
+	}
+
+	return s
+}
+
+// AddSlice includes the items of the slice to the set.
+func (s *setAny[T]) AddSlice(items []T) Set[T] { return s.Add(items...) }
+
+// AddSliceCount includes the items of the slice to the set and returns the
+// number of the new ones.
+func (s *setAny[T]) AddSliceCount(items []T) int { return insertCount[T](s, items) }
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set.
+func (s *setAny[T]) RemoveChanged(items ...T) bool { return removeChanged[T](s, items) }
+
+// MergeChanged merges t into s and reports whether any item was added.
+func (s *setAny[T]) MergeChanged(t Set[T]) bool { return mergeChanged[T](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setAny[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before.
+func (s *setAny[T]) Insert(item T) bool { return s.insert(s.hash(item), item) }
+
+// insert includes the item of hash h, and reports whether it wasn't there
+// before.
+func (s *setAny[T]) insert(h uint64, item T) bool {
+	if s.find(h, item) >= 0 {
+		return false
+	}
+	s.lazyInit()
+	s.m[h] = append(s.m[h], item)
+	s.size++
+
+	return true
+}
+
+// AddErr is like Add, but returns the error of hashing an item instead of
+// panicking. If any item fails, none of them is added.
+func (s *setAny[T]) AddErr(items ...T) error {
+	hashes := make([]uint64, len(items))
+	for i, item := range items {
+		h, err := s.hashErr(item)
 		if err != nil {
-			panic(err)
+			return err
 		}
-		s[h] = item
+		hashes[i] = h
 	}
 
-	return s
+	for i, item := range items {
+		s.insert(hashes[i], item)
+	}
+
+	return nil
+}
+
+// HasErr is like Has, but returns the error of hashing an item instead of
+// panicking.
+func (s *setAny[T]) HasErr(items ...T) (bool, error) {
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	for _, item := range items {
+		h, err := s.hashErr(item)
+		if err != nil {
+			return false, err
+		}
+		if s.find(h, item) < 0 {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 // Remove deletes the specified items from the set.  The underlying Set s is
 // modified. If passed nothing it silently returns.
-func (s setAny[T]) Remove(items ...T) Set[T] {
+func (s *setAny[T]) Remove(items ...T) Set[T] {
 	for _, item := range items {
-		delete(s, mushHash(item))
+		h := s.hash(item)
+		if i := s.find(h, item); i >= 0 {
+			s.removeAt(h, i)
+		}
 	}
 	return s
 }
 
 // Pop  deletes and return an item from the set. The underlying Set s is
 // modified. If set is empty, nil is returned.
-func (s setAny[T]) Pop() (T, bool) {
-	for h, item := range s {
-		defer delete(s, h)
+func (s *setAny[T]) Pop() (T, bool) {
+	for h, bucket := range s.m {
+		item := bucket[len(bucket)-1]
+		s.removeAt(h, len(bucket)-1)
 		return item, true
 	}
 
@@ -52,86 +236,119 @@ func (s setAny[T]) Pop() (T, bool) {
 	return t, false
 }
 
+// PopN deletes and returns up to n items from the set. The underlying Set s is
+// modified. If set has less than n items, all of them are returned.
+func (s *setAny[T]) PopN(n int) []T { return popN(n, s.size, s.Pop) }
+
 // Has looks for the existence of items passed. It returns false if nothing is
 // passed. For multiple items it returns true only if all of  the items exist.
-func (s setAny[T]) Has(items ...T) bool {
+func (s *setAny[T]) Has(items ...T) bool {
 	// assume checked for empty item, which not exist
 	if len(items) == 0 {
 		return false
 	}
 
 	for _, item := range items {
-		if _, ok := s[mushHash(item)]; !ok {
+		if !s.has(item) {
 			return false
 		}
 	}
 	return true
 }
 
-func (s setAny[T]) Size() int     { return len(s) }
-func (s setAny[T]) Clear()        { s = make(map[uint64]T) }
-func (s setAny[T]) IsEmpty() bool { return s.Size() == 0 }
-func (s setAny[T]) IsEqual(t Set[T]) bool {
+// HasAny reports whether at least one of the passed items exists.
+func (s *setAny[T]) HasAny(items ...T) bool {
+	for _, item := range items {
+		if s.has(item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *setAny[T]) Size() int     { return s.size }
+func (s *setAny[T]) Len() int      { return s.size }
+func (s *setAny[T]) Clear()        { s.m, s.size = make(map[uint64][]T), 0 }
+func (s *setAny[T]) IsEmpty() bool { return s.Size() == 0 }
+func (s *setAny[T]) IsEqual(t Set[T]) bool {
 	// Force locking only if given set is threadsafe.
-	if conv, ok := t.(rwLocker); ok {
+	if conv, ok := t.(lockedSet[T]); ok {
 		conv.RLock()
 		defer conv.RUnlock()
+		t = conv.unlocked()
 	}
 
 	// return false if they are no the same size
-	if sameSize := len(s) == t.Size(); !sameSize {
+	if sameSize := s.size == t.Size(); !sameSize {
 		return false
 	}
 
-	return t.Each(func(item T) bool {
-		_, ok := s[mushHash(item)]
-		return ok // if false, Each() will end
-	})
+	return t.Each(s.has) // if false, Each() will end
 }
 
 // IsSubset tests whether t is a subset of s.
-func (s setAny[T]) IsSubset(t Set[T]) bool {
-	return t.Each(func(item T) bool {
-		_, ok := s[mushHash(item)]
-		return ok
-	})
+func (s *setAny[T]) IsSubset(t Set[T]) bool {
+	if t.Size() > s.size {
+		return false
+	}
+
+	return t.Each(s.has)
 }
 
 // IsSuperset tests whether t is a superset of s.
-func (s setAny[T]) IsSuperset(t Set[T]) bool { return t.IsSubset(s) }
+func (s *setAny[T]) IsSuperset(t Set[T]) bool { return t.IsSubset(s) }
 
 // Each traverses the items in the Set, calling the provided function for each
 // set member. Traversal will continue until all items in the Set have been
 // visited, or if the closure returns false.
-func (s setAny[T]) Each(f func(item T) bool) bool {
-	for _, item := range s {
-		if !f(item) {
-			return false
+func (s *setAny[T]) Each(f func(item T) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set.
+func (s *setAny[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, bucket := range s.m {
+			for _, item := range bucket {
+				if !yield(item) {
+					return
+				}
+			}
 		}
 	}
-
-	return true
 }
 
 // Copy returns a new Set with a copy of s.
-func (s setAny[T]) Copy() Set[T] {
-	u := make(setAny[T])
-	for h, item := range s {
-		u[h] = item
+func (s *setAny[T]) Copy() Set[T] {
+	u := &setAny[T]{m: make(map[uint64][]T, len(s.m)), size: s.size, key: s.key, eq: s.eq}
+	for h, bucket := range s.m {
+		u.m[h] = append([]T(nil), bucket...)
+	}
+	return u
+}
+
+// Filter returns a new Set with the items of s satisfying the predicate.
+func (s *setAny[T]) Filter(f func(item T) bool) Set[T] {
+	u := &setAny[T]{m: make(map[uint64][]T), key: s.key, eq: s.eq}
+	for h, bucket := range s.m {
+		for _, item := range bucket {
+			if f(item) {
+				u.m[h] = append(u.m[h], item)
+				u.size++
+			}
+		}
 	}
 	return u
 }
 
 // String returns a string representation of s
-func (s setAny[T]) String() string { return stringSet[T](s) }
+func (s *setAny[T]) String() string { return stringSet[T](s) }
 
-// List returns a slice of all items. There is also StringSlice() and
-// IntSlice() methods for returning slices of type string or int.
-func (s setAny[T]) List() []T {
-	list := make([]T, 0, len(s))
+// List returns a slice of all items. There are also the StringSlice and
+// IntSlice functions returning slices of type string or int.
+func (s *setAny[T]) List() []T {
+	list := make([]T, 0, s.size)
 
-	for _, item := range s {
-		list = append(list, item)
+	for _, bucket := range s.m {
+		list = append(list, bucket...)
 	}
 
 	return list
@@ -139,15 +356,165 @@ func (s setAny[T]) List() []T {
 
 // Merge is like Union, however it modifies the current set it's applied on
 // with the given t set.
-func (s setAny[T]) Merge(t Set[T]) Set[T] {
+func (s *setAny[T]) Merge(t Set[T]) Set[T] {
 	t.Each(func(item T) bool {
-		s[mushHash(item)] = item
+		s.Add(item)
 		return true
 	})
 
 	return s
 }
 
-// it's not the opposite of Merge.
-// Separate removes the set items containing in t from set s. Please aware that
-func (s setAny[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
+// RemoveIf deletes every item of s satisfying the predicate. The underlying
+// Set s is modified.
+func (s *setAny[T]) RemoveIf(f func(item T) bool) Set[T] {
+	for h, bucket := range s.m {
+		kept := bucket[:0]
+		for _, item := range bucket {
+			if f(item) {
+				s.size--
+			} else {
+				kept = append(kept, item)
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(s.m, h)
+		} else {
+			s.m[h] = kept
+		}
+	}
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *setAny[T]) Retain(f func(item T) bool) Set[T] { return s.RemoveIf(not(f)) }
+
+// Separate removes the items of t from s. The underlying Set s is modified, use
+// Without to get a new set instead. Please aware that it's not the opposite
+// of Merge.
+func (s *setAny[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
+
+// hashSeed seeds the hashes of comparable items. It's random, so the hashes
+// are stable only within a single process.
+var hashSeed = maphash.MakeSeed()
+
+// HashSet returns a hash of the items of s, which doesn't depend on the order
+// they are iterated in, so it can be used as a cache key: equal sets always
+// have equal hashes, while unequal ones rarely collide. Items implementing
+// Hashable are hashed by their Hash method, any others with hash/maphash. The
+// hashes of the latter differ between processes, so they must not be
+// persisted. Threadsafe sets are hashed under a single read lock.
+func HashSet[T comparable](s Set[T]) uint64 {
+	var sum, n uint64
+	s.Each(func(item T) bool {
+		sum += mixHash(hashItem(item)) // addition is commutative
+		n++
+		return true
+	})
+
+	return mixHash(sum ^ mixHash(n))
+}
+
+func hashItem[T comparable](item T) uint64 {
+	if h, ok := any(item).(Hashable); ok {
+		return mushHash(h)
+	}
+
+	return hashComparable(hashSeed, item)
+}
+
+// hashComparable hashes the comparable item with the seed, so items equal by
+// the == operator get equal hashes. Strings and integers are hashed directly,
+// any other items are walked by reflection, like == compares them.
+func hashComparable[T comparable](seed maphash.Seed, item T) uint64 {
+	switch v := any(item).(type) {
+	case string:
+		return maphash.String(seed, v)
+	case int:
+		return hashUint64(seed, uint64(v))
+	case int64:
+		return hashUint64(seed, uint64(v))
+	case uint64:
+		return hashUint64(seed, v)
+	}
+
+	var h maphash.Hash
+	h.SetSeed(seed)
+	writeHash(&h, reflect.ValueOf(item))
+
+	return h.Sum64()
+}
+
+func hashUint64(seed maphash.Seed, v uint64) uint64 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+
+	return maphash.Bytes(seed, b[:])
+}
+
+// writeHash writes the value to h the way == compares it: pointers and
+// channels by their address, floats with -0 folded into 0, structs by their
+// non-blank fields and interfaces by their dynamic value.
+func writeHash(h *maphash.Hash, v reflect.Value) {
+	var b [8]byte
+	writeUint64 := func(u uint64) {
+		binary.LittleEndian.PutUint64(b[:], u)
+		h.Write(b[:])
+	}
+	writeFloat := func(f float64) {
+		if f == 0 {
+			f = 0 // -0 equals 0, so it must hash the same
+		}
+		writeUint64(math.Float64bits(f)) // NaN equals nothing, any hash goes
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		h.WriteByte(0)
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(real(v.Complex()))
+		writeFloat(imag(v.Complex()))
+	case reflect.String:
+		writeUint64(uint64(v.Len())) // so "ab", "" and "a", "b" fields differ
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(uint64(v.Pointer()))
+	case reflect.Interface:
+		writeHash(h, v.Elem())
+	case reflect.Array:
+		for i := range v.Len() {
+			writeHash(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).Name != "_" { // == ignores blank fields
+				writeHash(h, v.Field(i))
+			}
+		}
+	}
+}
+
+// mixHash spreads the bits of h, so sums of similar hashes don't collide, as
+// the finalizer of SplitMix64 does.
+func mixHash(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+
+	return h
+}
@@ -0,0 +1,242 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type hashInt int
+
+func (h hashInt) Hash() (uint64, error) { return uint64(h), nil }
+
+func TestSetAny_PopN(t *testing.T) {
+	s := newAnyNonTS[hashInt](1, 2, 3, 4, 5).(*setAny[hashInt])
+
+	popped := s.PopN(3)
+	if len(popped) != 3 {
+		t.Fatal("PopN: expected three items, got", len(popped))
+	}
+	if s.Size() != 2 {
+		t.Error("PopN: two items should remain, got", s.Size())
+	}
+
+	for _, item := range popped {
+		if s.Has(item) {
+			t.Error("PopN: popped item should not exist:", item)
+		}
+	}
+
+	for i := hashInt(1); i <= 5; i++ {
+		if !s.Has(i) && !containsItem(popped, i) {
+			t.Error("PopN: item neither popped nor remaining:", i)
+		}
+	}
+}
+
+func containsItem[T comparable](items []T, item T) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSetAny_Clear(t *testing.T) {
+	s := NewAnyNonTS[hashInt](1, 2, 3)
+
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Error("Clear: set should be empty, got", s)
+	}
+}
+
+// collidingItem hashes all the items to the same value.
+type collidingItem string
+
+func (collidingItem) Hash() (uint64, error) { return 42, nil }
+
+func TestSetAny_collisions(t *testing.T) {
+	for name, s := range map[string]Set[collidingItem]{
+		"NewAnyNonTS": NewAnyNonTS[collidingItem]("a", "b"),
+		"NewAny":      NewAny[collidingItem]("a", "b"),
+	} {
+		if s.Size() != 2 || !s.Has("a", "b") {
+			t.Errorf("%s: items sharing a hash should both be kept, got %v", name, s)
+		}
+		if s.Has("c") {
+			t.Errorf("%s: item sharing a hash should not be reported as member", name)
+		}
+
+		s.Remove("a")
+		if s.Size() != 1 || s.Has("a") || !s.Has("b") {
+			t.Errorf("%s: only the removed item should be gone, got %v", name, s)
+		}
+	}
+}
+
+// taggedItem can't be compared with the == operator and has no Equal method,
+// so items sharing a hash can't be told apart.
+type taggedItem struct {
+	id   uint64
+	tags []string
+}
+
+func (i taggedItem) Hash() (uint64, error) { return i.id % 2, nil }
+
+func TestSetAny_uncomparable(t *testing.T) {
+	for name, s := range map[string]Set[taggedItem]{
+		"NewAnyNonTS": NewAnyNonTS[taggedItem](),
+		"NewAny":      NewAny[taggedItem](),
+	} {
+		s.Add(taggedItem{1, []string{"a"}}, taggedItem{2, nil}) // distinct hashes
+		if s.Size() != 2 {
+			t.Errorf("%s: items with distinct hashes should be added, got %v", name, s)
+		}
+
+		func() {
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.Contains(msg, "NewFunc") {
+					t.Errorf("%s: Add: expected a panic pointing to NewFunc, got %q", name, msg)
+				}
+			}()
+			s.Add(taggedItem{3, nil}) // lands in the occupied bucket
+		}()
+	}
+}
+
+func Test_HashSet(t *testing.T) {
+	a := newTS[string]()
+	b := newNonTS[string]()
+	for i := 0; i < 100; i++ {
+		a.Add(strconv.Itoa(i))
+		b.Add(strconv.Itoa(99 - i))
+	}
+
+	if HashSet(a) != HashSet(b) {
+		t.Error("HashSet: equal sets should hash equal regardless of insertion order")
+	}
+	if HashSet(newNonTS[hashInt](1, 2)) != HashSet(NewAny[hashInt](2, 1)) {
+		t.Error("HashSet: equal sets of hashable items should hash equal")
+	}
+
+	type point struct{ X float64 }
+	zero, negZero := newNonTS(point{0}), newNonTS(point{math.Copysign(0, -1)})
+	if !zero.IsEqual(negZero) || HashSet(zero) != HashSet(negZero) {
+		t.Error("HashSet: equal sets with -0 and 0 fields should hash equal")
+	}
+
+	seen := map[uint64]Set[string]{}
+	for _, s := range []Set[string]{
+		newNonTS[string](), newNonTS(""), newNonTS("a"), newNonTS("b"),
+		newNonTS("a", "b"), newNonTS("a", "c"), newNonTS("a", "b", "c"),
+	} {
+		h := HashSet(s)
+		if other, ok := seen[h]; ok {
+			t.Errorf("HashSet: %v and %v collide", s, other)
+		}
+		seen[h] = s
+	}
+}
+
+func Test_hashComparable(t *testing.T) {
+	type point struct{ X, Y int }
+	a, b := &point{1, 2}, &point{1, 2}
+	negZero := math.Copysign(0, -1)
+
+	if hashComparable(hashSeed, point{1, 2}) != hashComparable(hashSeed, point{1, 2}) ||
+		hashComparable(hashSeed, 0.0) != hashComparable(hashSeed, negZero) ||
+		hashComparable(hashSeed, float32(0)) != hashComparable(hashSeed, float32(negZero)) ||
+		hashComparable(hashSeed, [1]float64{0}) != hashComparable(hashSeed, [1]float64{negZero}) ||
+		hashComparable[any](hashSeed, point{1, 2}) != hashComparable[any](hashSeed, point{1, 2}) ||
+		hashComparable(hashSeed, a) != hashComparable(hashSeed, a) {
+		t.Error("hashComparable: equal items should hash equal")
+	}
+	if hashComparable(hashSeed, a) == hashComparable(hashSeed, b) ||
+		hashComparable(hashSeed, 1) == hashComparable(hashSeed, 2) {
+		t.Error("hashComparable: distinct items are expected not to collide")
+	}
+	if h := hashComparable(hashSeed, a); func() uint64 { a.X = 3; return hashComparable(hashSeed, a) }() != h {
+		t.Error("hashComparable: a pointer should hash by its address, not the pointee")
+	}
+}
+
+// route can't be compared with the == operator, as it has a slice field.
+type route struct {
+	name  string
+	stops []string
+}
+
+func TestNewFunc(t *testing.T) {
+	eq := func(a, b route) bool { return a.name == b.name && slices.Equal(a.stops, b.stops) }
+	key := func(r route) uint64 { return uint64(len(r.name)) } // collides often
+
+	s := NewFunc(eq, key, route{"one", []string{"a", "b"}}, route{"two", []string{"b"}})
+	s.Add(route{"one", []string{"a", "b"}}) // equal to an item, not added
+	s.Add(route{"one", []string{"b", "a"}}) // same key and name, other stops
+
+	if s.Size() != 3 {
+		t.Error("NewFunc: expected three distinct routes, got", s)
+	}
+	if !s.Has(route{"two", []string{"b"}}) || s.Has(route{"two", []string{"c"}}) {
+		t.Error("Has: routes should be told apart by eq")
+	}
+
+	s.Remove(route{"one", []string{"a", "b"}})
+	if s.Size() != 2 || s.Has(route{"one", []string{"a", "b"}}) || !s.Has(route{"one", []string{"b", "a"}}) {
+		t.Error("Remove: only the equal route should be removed, got", s)
+	}
+
+	u := s.Copy()
+	u.Add(route{"two", []string{"b"}})
+	if u.Size() != 2 || !u.IsEqual(s) {
+		t.Error("Copy: copy should keep comparing routes by eq, got", u)
+	}
+	if f := s.Filter(func(r route) bool { return r.name == "two" }); !f.Has(route{"two", []string{"b"}}) || f.Insert(route{"two", []string{"b"}}) {
+		t.Error("Filter: filtered set should keep comparing routes by eq, got", f)
+	}
+}
+
+// brokenHash fails to hash negative values.
+type brokenHash int
+
+func (b brokenHash) Hash() (uint64, error) {
+	if b < 0 {
+		return 0, errors.New("negative value")
+	}
+	return uint64(b), nil
+}
+
+func TestSetAny_AddErr(t *testing.T) {
+	for name, s := range map[string]Set[brokenHash]{
+		"NewAny":      NewAny[brokenHash](1),
+		"NewAnyNonTS": NewAnyNonTS[brokenHash](1),
+	} {
+		f := s.(FallibleSet[brokenHash])
+
+		if err := f.AddErr(2, -3, 4); err == nil || err.Error() != "negative value" {
+			t.Errorf("%s: AddErr: expected the hash error, got %v", name, err)
+		}
+		if s.Size() != 1 {
+			t.Errorf("%s: AddErr: nothing should be added if an item fails, got %v", name, s)
+		}
+		if err := f.AddErr(2, 4); err != nil || !s.Has(1, 2, 4) {
+			t.Errorf("%s: AddErr: expected the items to be added, got %v, %v", name, err, s)
+		}
+
+		if ok, err := f.HasErr(1, -1); ok || err == nil {
+			t.Errorf("%s: HasErr: expected the hash error, got %v", name, err)
+		}
+		if ok, err := f.HasErr(1, 2); !ok || err != nil {
+			t.Errorf("%s: HasErr: expected the items to be found, got %v", name, err)
+		}
+		if ok, err := f.HasErr(); ok || err != nil {
+			t.Errorf("%s: HasErr: expected false for no items, got %v", name, err)
+		}
+	}
+}
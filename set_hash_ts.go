@@ -0,0 +1,312 @@
+package set
+
+import (
+	"iter"
+	"sync"
+)
+
+// setAnym defines a thread safe set of hashable items, or of the items hashed
+// and compared by the functions given to NewFunc.
+type setAnym[T any] struct {
+	setAny[T]
+	sync.RWMutex // we name it because we don't want to expose it
+	lockOrder
+}
+
+// newAnyTS creates and initializes a new threadsafe Set of hashable items.
+func newAnyTS[T Hashable](items ...T) Set[T] {
+	return (&setAnym[T]{setAny: setAny[T]{m: make(map[uint64][]T)}}).Add(items...)
+}
+
+// newFuncTS creates and initializes a new threadsafe Set of items, which are
+// hashed by key and compared by eq.
+func newFuncTS[T any](eq func(a, b T) bool, key func(T) uint64, items ...T) Set[T] {
+	s := &setAnym[T]{setAny: setAny[T]{m: make(map[uint64][]T), key: key, eq: eq}}
+	return s.Add(items...)
+}
+
+// Add includes the specified items (one or more) to the set. The underlying
+// Set s is modified. If passed nothing it silently returns.
+func (s *setAnym[T]) Add(items ...T) Set[T] {
+	if len(items) == 0 {
+		return s
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.setAny.Add(items...)
+
+	return s
+}
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before, atomically.
+func (s *setAnym[T]) Insert(item T) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.setAny.Insert(item)
+}
+
+// AddSlice includes the items of the slice to the set.
+func (s *setAnym[T]) AddSlice(items []T) Set[T] { return s.Add(items...) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setAnym[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// AddAndSize includes the specified items to the set and returns the size of
+// the set right after, both under the same lock.
+func (s *setAnym[T]) AddAndSize(items ...T) int {
+	s.Lock()
+	defer s.Unlock()
+	s.setAny.Add(items...)
+
+	return s.setAny.Size()
+}
+
+// Len is an alias of Size.
+func (s *setAnym[T]) Len() int { return s.Size() }
+
+// Remove deletes the specified items from the set.  The underlying Set s is
+// modified. If passed nothing it silently returns.
+func (s *setAnym[T]) Remove(items ...T) Set[T] {
+	if len(items) == 0 {
+		return s
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.setAny.Remove(items...)
+
+	return s
+}
+
+// RemoveChanged deletes the items from the set, like Remove, and reports
+// whether any of them was in the set, under the write lock.
+func (s *setAnym[T]) RemoveChanged(items ...T) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	before := s.setAny.size
+	s.setAny.Remove(items...)
+
+	return s.setAny.size != before
+}
+
+// Pop deletes and return an item from the set. The underlying Set s is
+// modified. If set is empty, false is returned.
+func (s *setAnym[T]) Pop() (T, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.setAny.Pop()
+}
+
+// PopN deletes and returns up to n items from the set.
+func (s *setAnym[T]) PopN(n int) []T {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.setAny.PopN(n)
+}
+
+// Claim removes the item and reports whether it was in the set, both under
+// the same write lock.
+func (s *setAnym[T]) Claim(item T) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.setAny.Has(item) {
+		return false
+	}
+	s.setAny.Remove(item)
+
+	return true
+}
+
+// Has looks for the existence of items passed. It returns false if nothing is
+// passed. For multiple items it returns true only if all of  the items exist.
+func (s *setAnym[T]) Has(items ...T) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.setAny.Has(items...)
+}
+
+// HasAny reports whether at least one of the passed items exists. The set is
+// locked once for all the items.
+func (s *setAnym[T]) HasAny(items ...T) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.setAny.HasAny(items...)
+}
+
+// AddSliceCount includes the items of the slice to the set and returns the
+// number of the new ones, under the write lock.
+func (s *setAnym[T]) AddSliceCount(items []T) int {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.setAny.AddSliceCount(items)
+}
+
+// AddErr is like Add, but returns the error of hashing an item instead of
+// panicking. If any item fails, none of them is added.
+func (s *setAnym[T]) AddErr(items ...T) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.setAny.AddErr(items...)
+}
+
+// HasErr is like Has, but returns the error of hashing an item instead of
+// panicking.
+func (s *setAnym[T]) HasErr(items ...T) (bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.setAny.HasErr(items...)
+}
+
+// Size returns the number of items in a set.
+func (s *setAnym[T]) Size() int {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.setAny.Size()
+}
+
+// Clear removes all items from the set.
+func (s *setAnym[T]) Clear() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.setAny.Clear()
+}
+
+// IsEmpty reports whether the Set is empty.
+func (s *setAnym[T]) IsEmpty() bool { return s.Size() == 0 }
+
+// IsEqual test whether s and t are the same in size and have the same items.
+func (s *setAnym[T]) IsEqual(t Set[T]) bool {
+	if t == Set[T](s) {
+		return true // no need to lock, even once
+	}
+
+	// Lock both sets in a canonical order if given set is threadsafe, so
+	// concurrent s.IsEqual(t) and t.IsEqual(s) can't deadlock.
+	if conv, ok := t.(lockedSet[T]); ok {
+		defer rLockOrdered(s, conv)()
+		return s.setAny.IsEqual(conv.unlocked())
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.setAny.IsEqual(t)
+}
+
+// IsSubset tests whether t is a subset of s.
+func (s *setAnym[T]) IsSubset(t Set[T]) bool {
+	// Like IsEqual, lock both sets in a canonical order, as IsSuperset calls
+	// IsSubset the other way round.
+	if conv, ok := t.(lockedSet[T]); ok {
+		defer rLockOrdered(s, conv)()
+		return s.setAny.IsSubset(conv.unlocked())
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.setAny.IsSubset(t)
+}
+
+// IsSuperset tests whether t is a superset of s.
+func (s *setAnym[T]) IsSuperset(t Set[T]) bool { return t.IsSubset(s) }
+
+// Each traverses the items in the Set, calling the provided function for each
+// set member. Traversal will continue until all items in the Set have been
+// visited, or if the closure returns false.
+func (s *setAnym[T]) Each(f func(item T) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set. The read lock is held
+// for the whole iteration.
+func (s *setAnym[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.RLock()
+		defer s.RUnlock()
+
+		s.setAny.All()(yield)
+	}
+}
+
+// String returns a string representation of s
+func (s *setAnym[T]) String() string { return stringSet[T](s) }
+
+// List returns a slice of all items.
+func (s *setAnym[T]) List() []T {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.setAny.List()
+}
+
+// Copy returns a new Set with a copy of s.
+func (s *setAnym[T]) Copy() Set[T] {
+	s.RLock()
+	defer s.RUnlock()
+
+	return &setAnym[T]{setAny: *s.setAny.Copy().(*setAny[T])}
+}
+
+// Filter returns a new Set with the items of s satisfying the predicate. The
+// read lock is held for the whole filtering.
+func (s *setAnym[T]) Filter(f func(item T) bool) Set[T] {
+	s.RLock()
+	defer s.RUnlock()
+
+	return &setAnym[T]{setAny: *s.setAny.Filter(f).(*setAny[T])}
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set. If t is threadsafe too, both locks are taken in the
+// canonical order, so concurrent s.Merge(t) and t.Merge(s) can't deadlock.
+func (s *setAnym[T]) Merge(t Set[T]) Set[T] {
+	s.MergeChanged(t)
+	return s
+}
+
+// MergeChanged merges t into s, like Merge, and reports whether any item was
+// added, under the same locks.
+func (s *setAnym[T]) MergeChanged(t Set[T]) bool {
+	if conv, ok := t.(lockedSet[T]); ok {
+		defer lockWithReader(s, conv)()
+		t = conv.unlocked()
+	} else {
+		s.Lock()
+		defer s.Unlock()
+	}
+	before := s.setAny.size
+	s.setAny.Merge(t)
+
+	return s.setAny.size != before
+}
+
+func (s *setAnym[T]) unlocked() Set[T] { return &s.setAny }
+
+// RemoveIf deletes every item of s satisfying the predicate, under the write
+// lock.
+func (s *setAnym[T]) RemoveIf(f func(item T) bool) Set[T] {
+	s.Lock()
+	defer s.Unlock()
+	s.setAny.RemoveIf(f)
+
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *setAnym[T]) Retain(f func(item T) bool) Set[T] { return s.RemoveIf(not(f)) }
+
+// Separate removes the set items containing in t from set s.
+func (s *setAnym[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
@@ -0,0 +1,96 @@
+package set
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetAnyTS_RaceAddHas(t *testing.T) {
+	// "go test -race" should detect this if the set is not thread-safe.
+	s := NewAny[hashInt](0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(i hashInt) {
+			defer wg.Done()
+			s.Add(i)
+		}(hashInt(i))
+		go func(i hashInt) {
+			defer wg.Done()
+			s.Has(i)
+			s.Each(func(hashInt) bool { return true })
+		}(hashInt(i))
+	}
+	wg.Wait()
+
+	if s.Size() != 100 {
+		t.Error("NewAny: expected 100 items after concurrent adds, got", s.Size())
+	}
+}
+
+func TestSetAnyTS_Pop(t *testing.T) {
+	s := NewAny[hashInt](1, 2, 3)
+
+	a, ok := s.Pop()
+	if !ok || s.Size() != 2 || s.Has(a) {
+		t.Error("Pop: popped item should be removed from the set")
+	}
+
+	s.Pop()
+	s.Pop()
+	if _, ok := s.Pop(); ok {
+		t.Error("Pop: should return false because set is empty")
+	}
+
+	c := NewAny[hashInt](1, 2).Copy()
+	if !c.IsEqual(NewAnyNonTS[hashInt](1, 2)) {
+		t.Error("Copy: copied set should have the same items, got", c)
+	}
+}
+
+func TestSetAnyTS_IsSubset_crossed(t *testing.T) {
+	// Check the subsets in both directions while writers contend for the
+	// locks, like TestSet_IsEqual_crossed does. The sets are big, so the
+	// checks hold the locks long enough to be preempted even on a single CPU.
+	items := make([]hashInt, 10000)
+	for i := range items {
+		items[i] = hashInt(i)
+	}
+	a, b := NewAny(items...), NewAny(items...)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		for _, f := range []func(i int){
+			func(int) { a.IsSubset(b) },
+			func(int) { b.IsSubset(a) },
+			func(i int) { a.Add(hashInt(i)) },
+			func(i int) { b.Add(hashInt(i)) },
+		} {
+			for g := 0; g < 4; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 25; i++ {
+						f(i)
+					}
+				}()
+			}
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("IsSubset: crossed checks deadlocked")
+	}
+
+	if !a.IsSubset(b) || !b.IsSubset(a) {
+		t.Error("IsSubset: sets with the same items should be subsets of each other")
+	}
+}
@@ -0,0 +1,70 @@
+package set
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// IntervalSet is a set of closed [start, end] intervals over a continuous
+// domain. Overlapping and touching intervals are coalesced as they are added,
+// so the set always holds disjoint intervals sorted by their start.
+type IntervalSet[T constraints.Ordered] interface {
+	// Add includes the [start, end] interval to the set, merging it with
+	// every interval it overlaps or touches. If start is greater than end, the
+	// bounds are swapped.
+	Add(start, end T)
+	// Contains reports whether point lies within any interval of the set.
+	Contains(point T) bool
+	// Intervals returns the merged intervals, sorted by their start.
+	Intervals() [][2]T
+}
+
+// intervals is a non-threadsafe IntervalSet, which keeps disjoint intervals
+// sorted by their start.
+type intervals[T constraints.Ordered] struct {
+	list [][2]T
+}
+
+var _ IntervalSet[int] = (*intervals[int])(nil)
+
+// NewIntervalSet creates and initializes a new non-threadsafe IntervalSet.
+func NewIntervalSet[T constraints.Ordered]() IntervalSet[T] { return &intervals[T]{} }
+
+func (s *intervals[T]) Add(start, end T) {
+	if start > end {
+		start, end = end, start
+	}
+
+	merged := make([][2]T, 0, len(s.list)+1)
+	i := 0
+	// intervals entirely before the new one
+	for ; i < len(s.list) && s.list[i][1] < start; i++ {
+		merged = append(merged, s.list[i])
+	}
+	// intervals overlapping or touching the new one
+	for ; i < len(s.list) && s.list[i][0] <= end; i++ {
+		if s.list[i][0] < start {
+			start = s.list[i][0]
+		}
+		if s.list[i][1] > end {
+			end = s.list[i][1]
+		}
+	}
+	merged = append(merged, [2]T{start, end})
+	// intervals entirely after the new one
+	merged = append(merged, s.list[i:]...)
+
+	s.list = merged
+}
+
+func (s *intervals[T]) Contains(point T) bool {
+	// first interval which doesn't end before the point
+	i := sort.Search(len(s.list), func(i int) bool { return s.list[i][1] >= point })
+
+	return i < len(s.list) && s.list[i][0] <= point
+}
+
+func (s *intervals[T]) Intervals() [][2]T {
+	return append(make([][2]T, 0, len(s.list)), s.list...)
+}
@@ -0,0 +1,42 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIntervalSet_Add(t *testing.T) {
+	s := NewIntervalSet[int]()
+	s.Add(1, 3)
+	s.Add(2, 5)
+
+	if got := s.Intervals(); !reflect.DeepEqual(got, [][2]int{{1, 5}}) {
+		t.Error("Add: overlapping intervals should be merged, got", got)
+	}
+
+	s.Add(10, 7) // reversed bounds
+	s.Add(5, 6)  // touching
+
+	if got := s.Intervals(); !reflect.DeepEqual(got, [][2]int{{1, 6}, {7, 10}}) {
+		t.Error("Add: intervals should stay sorted and disjoint, got", got)
+	}
+
+	s.Add(0, 20)
+	if got := s.Intervals(); !reflect.DeepEqual(got, [][2]int{{0, 20}}) {
+		t.Error("Add: covering interval should swallow the others, got", got)
+	}
+}
+
+func TestIntervalSet_Contains(t *testing.T) {
+	s := NewIntervalSet[float64]()
+	s.Add(1, 2)
+	s.Add(4, 5.5)
+
+	for point, want := range map[float64]bool{
+		0: false, 1: true, 1.5: true, 2: true, 3: false, 5.5: true, 6: false,
+	} {
+		if got := s.Contains(point); got != want {
+			t.Errorf("Contains(%v): expected %v, got %v", point, want, got)
+		}
+	}
+}
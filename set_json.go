@@ -0,0 +1,199 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// encodeItems encodes the items as a JSON array. Items of ordered kinds
+// (integers, floats and strings) are sorted ascending, any others by their
+// encoding, so the same set is always encoded the same way.
+func encodeItems[T any](items []T) ([]byte, error) {
+	type encodedItem struct {
+		item    T
+		encoded json.RawMessage
+	}
+
+	encoded := make([]encodedItem, len(items))
+	for i, item := range items {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = encodedItem{item, b}
+	}
+
+	slices.SortFunc(encoded, func(a, b encodedItem) int {
+		return compareItems(a.item, b.item, a.encoded, b.encoded)
+	})
+
+	list := make([]json.RawMessage, len(encoded))
+	for i, item := range encoded {
+		list[i] = item.encoded
+	}
+
+	return json.Marshal(list)
+}
+
+// compareItems compares a and b by their value if they are of the same
+// ordered kind, otherwise by their encoded form.
+func compareItems(a, b any, encodedA, encodedB []byte) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() == vb.Kind() {
+		if c, ok := compareOrdered(va, vb); ok {
+			return c
+		}
+	}
+
+	return bytes.Compare(encodedA, encodedB)
+}
+
+// compareOrdered compares va and vb of the same kind by their value, and
+// reports whether the kind is ordered at all.
+func compareOrdered(va, vb reflect.Value) (int, bool) {
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(va.Int(), vb.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(va.Uint(), vb.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(va.Float(), vb.Float()), true
+	case reflect.String:
+		return strings.Compare(va.String(), vb.String()), true
+	default:
+		return 0, false
+	}
+}
+
+func decodeItems[T any](data []byte) ([]T, error) {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// MarshalJSON encodes the set as a JSON array of its items.
+func (s *set[T]) MarshalJSON() ([]byte, error) { return encodeItems(s.List()) }
+
+// UnmarshalJSON replaces the items of the set with the items of a JSON array.
+func (s *set[T]) UnmarshalJSON(data []byte) error {
+	items, err := decodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.m = make(map[T]struct{}, len(items))
+	s.Add(items...)
+
+	return nil
+}
+
+// MarshalJSON encodes the set as a JSON array of its items.
+func (s *setm[T]) MarshalJSON() ([]byte, error) { return encodeItems(s.List()) }
+
+// UnmarshalJSON replaces the items of the set with the items of a JSON array.
+func (s *setm[T]) UnmarshalJSON(data []byte) error {
+	items, err := decodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	s.clear()
+	s.add(items...)
+
+	return nil
+}
+
+// MarshalJSON encodes the set as a JSON array of its items.
+func (s *setAny[T]) MarshalJSON() ([]byte, error) { return encodeItems(s.List()) }
+
+// UnmarshalJSON replaces the items of the set with the items of a JSON array.
+func (s *setAny[T]) UnmarshalJSON(data []byte) error {
+	items, err := decodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
+
+// MarshalJSON encodes the set as a JSON array of its items.
+func (s *setAnym[T]) MarshalJSON() ([]byte, error) { return encodeItems(s.List()) }
+
+// UnmarshalJSON replaces the items of the set with the items of a JSON array.
+func (s *setAnym[T]) UnmarshalJSON(data []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.setAny.UnmarshalJSON(data)
+}
+
+// MarshalJSON encodes the set as a JSON array of its items, in the order they
+// were added.
+func (s *setOrdered[T]) MarshalJSON() ([]byte, error) { return json.Marshal(s.List()) }
+
+// UnmarshalJSON replaces the items of the set with the items of a JSON array.
+func (s *setOrdered[T]) UnmarshalJSON(data []byte) error {
+	items, err := decodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
+
+// UnmarshalJSON replaces the items of the set with the items of a JSON array,
+// keeping only the most recent ones within the window.
+func (s *setWindowed[T]) UnmarshalJSON(data []byte) error {
+	items, err := decodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
+
+// UnmarshalJSON replaces the items of the set with the normalized items of a
+// JSON array.
+func (s *setNormalized) UnmarshalJSON(data []byte) error {
+	items, err := decodeItems[string](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
+
+// UnmarshalJSON replaces the items of the set and the store with the items of
+// a JSON array.
+func (s *setPersistent[T]) UnmarshalJSON(data []byte) error {
+	items, err := decodeItems[T](data)
+	if err != nil {
+		return err
+	}
+
+	s.Clear()
+	s.Add(items...)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package set
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_JSON(t *testing.T) {
+	type point struct{ X, Y int }
+
+	testJSON(t, New(3, 1, 2), NewNonTS[int](), `[1,2,3]`)
+	testJSON(t, NewNonTS("b", "c", "a"), New[string](), `["a","b","c"]`)
+	testJSON(t, New(point{1, 2}, point{0, 0}), NewNonTS[point](), `[{"X":0,"Y":0},{"X":1,"Y":2}]`)
+	testJSON(t, NewFair("b", "c", "a"), NewFair[string](), `["b","c","a"]`)
+	testJSON(t, NewAny[hashInt](2, 1), NewAnyNonTS[hashInt](), `[1,2]`)
+}
+
+func testJSON[T comparable](t *testing.T, s, decoded Set[T], want string) {
+	t.Helper()
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON: expected %s, got %s", want, data)
+	}
+
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.IsEqual(s) {
+		t.Errorf("UnmarshalJSON: expected %v, got %v", s, decoded)
+	}
+}
+
+func Test_JSON_empty(t *testing.T) {
+	for name, s := range map[string]Set[string]{
+		"New":      New("a"),
+		"NewNonTS": NewNonTS("a"),
+	} {
+		if err := json.Unmarshal([]byte(`[]`), s); err != nil {
+			t.Fatal(err)
+		}
+		if !s.IsEmpty() {
+			t.Errorf("%s: empty array should replace the items, got %v", name, s)
+		}
+
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `[]` {
+			t.Errorf("%s: empty set should be encoded as an empty array, got %s", name, data)
+		}
+	}
+
+	var s setm[int]
+	if err := json.Unmarshal([]byte(`[1,2]`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Size() != 2 || !s.Has(1, 2) {
+		t.Error("UnmarshalJSON: zero value set should be initialized, got", &s)
+	}
+}
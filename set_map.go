@@ -0,0 +1,49 @@
+package set
+
+// ToMapWithValues returns a new map from every item of s to the value computed
+// for it. Threadsafe sets are snapshotted under a single read lock.
+func ToMapWithValues[T comparable, V any](s Set[T], value func(T) V) map[T]V {
+	m := make(map[T]V, s.Size())
+	s.Each(func(item T) bool {
+		m[item] = value(item)
+		return true
+	})
+
+	return m
+}
+
+// ToMap returns a new map with every item of s as a key. The map doesn't share
+// anything with s. Threadsafe sets are snapshotted under a single read lock.
+func ToMap[T comparable](s Set[T]) map[T]struct{} {
+	return ToMapWithValues(s, func(T) struct{} { return null{} })
+}
+
+// ToBoolMap returns a new map from every item of s to true. The map doesn't
+// share anything with s. Threadsafe sets are snapshotted under a single read
+// lock.
+func ToBoolMap[T comparable](s Set[T]) map[T]bool {
+	return ToMapWithValues(s, func(T) bool { return true })
+}
+
+// FromMapKeys creates a new threadsafe Set of the keys of m.
+func FromMapKeys[K comparable, V any](m map[K]V) Set[K] {
+	s := &setm[K]{set: set[K]{make(map[K]struct{}, len(m))}}
+	for key := range m {
+		s.m[key] = null{}
+	}
+
+	return s
+}
+
+// FromBoolMap creates a new threadsafe Set of the keys of m, which are mapped
+// to true.
+func FromBoolMap[K comparable](m map[K]bool) Set[K] {
+	s := newTS[K]()
+	for key, ok := range m {
+		if ok {
+			s.Add(key)
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,55 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ToMapWithValues(t *testing.T) {
+	s := newTS("go", "set", "fatih")
+
+	m := ToMapWithValues(s, func(item string) int { return len(item) })
+	if !reflect.DeepEqual(m, map[string]int{"go": 2, "set": 3, "fatih": 5}) {
+		t.Error("ToMapWithValues: expected the lengths of the items, got", m)
+	}
+}
+
+func Test_ToMap(t *testing.T) {
+	s := newTS("a", "b")
+
+	m := ToMap(s)
+	bools := ToBoolMap(s)
+	if !reflect.DeepEqual(m, map[string]struct{}{"a": {}, "b": {}}) {
+		t.Error("ToMap: expected the items as keys, got", m)
+	}
+	if !reflect.DeepEqual(bools, map[string]bool{"a": true, "b": true}) {
+		t.Error("ToBoolMap: expected the items mapped to true, got", bools)
+	}
+
+	s.Add("c")
+	s.Remove("a")
+	if len(m) != 2 || len(bools) != 2 || !bools["a"] {
+		t.Error("ToMap: returned maps should not change with the set")
+	}
+
+	m["d"] = struct{}{}
+	if s.Has("d") {
+		t.Error("ToMap: changing the returned map should not change the set")
+	}
+}
+
+func Test_FromMapKeys(t *testing.T) {
+	s := FromMapKeys(map[string]int{"a": 1, "b": 2})
+	if !s.IsEqual(newNonTS("a", "b")) {
+		t.Error("FromMapKeys: expected the keys of the map, got", s)
+	}
+
+	s = FromBoolMap(map[string]bool{"a": true, "b": false, "c": true})
+	if !s.IsEqual(newNonTS("a", "c")) {
+		t.Error("FromBoolMap: expected only the keys mapped to true, got", s)
+	}
+
+	if s = FromMapKeys(map[string]int(nil)); !s.IsEmpty() {
+		t.Error("FromMapKeys: nil map should give an empty set, got", s)
+	}
+}
@@ -0,0 +1,110 @@
+package set
+
+// setNormalized is a non-threadsafe set of strings, which applies a
+// normalization to every item it is given.
+type setNormalized struct {
+	set[string]
+	normalize func(string) string
+}
+
+var _ Set[string] = (*setNormalized)(nil)
+
+// NewNormalized creates a new non-threadsafe set of strings, which applies
+// normalize to the items passed to every method, so items normalized to the
+// same string are considered equal, e.g. "café" and "CAFE" with a normalizer
+// stripping accents and folding case. Items are stored in their normalized
+// form.
+func NewNormalized(normalize func(string) string) Set[string] {
+	return &setNormalized{set: set[string]{make(map[string]struct{})}, normalize: normalize}
+}
+
+func (s *setNormalized) normalized(items []string) []string {
+	n := make([]string, len(items))
+	for i, item := range items {
+		n[i] = s.normalize(item)
+	}
+	return n
+}
+
+// Add includes the specified items (one or more) to the set. The underlying
+// Set s is modified. If passed nothing it silently returns.
+func (s *setNormalized) Add(items ...string) Set[string] {
+	s.set.Add(s.normalized(items)...)
+	return s
+}
+
+// Insert includes the normalized item to the set and reports whether it
+// wasn't there before.
+func (s *setNormalized) Insert(item string) bool { return s.set.Insert(s.normalize(item)) }
+
+// AddSlice includes the items of the slice to the set.
+func (s *setNormalized) AddSlice(items []string) Set[string] { return s.Add(items...) }
+
+// AddSliceCount includes the normalized items of the slice to the set and
+// returns the number of the new ones.
+func (s *setNormalized) AddSliceCount(items []string) int {
+	return s.set.AddSliceCount(s.normalized(items))
+}
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set.
+func (s *setNormalized) RemoveChanged(items ...string) bool { return removeChanged[string](s, items) }
+
+// MergeChanged merges t into s and reports whether any item was added.
+func (s *setNormalized) MergeChanged(t Set[string]) bool { return mergeChanged[string](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setNormalized) RemoveSlice(items []string) Set[string] { return s.Remove(items...) }
+
+// Remove deletes the specified items from the set.  The underlying Set s is
+// modified. If passed nothing it silently returns.
+func (s *setNormalized) Remove(items ...string) Set[string] {
+	s.set.Remove(s.normalized(items)...)
+	return s
+}
+
+// Has looks for the existence of items passed. It returns false if nothing is
+// passed. For multiple items it returns true only if all of  the items exist.
+func (s *setNormalized) Has(items ...string) bool { return s.set.Has(s.normalized(items)...) }
+
+// HasAny reports whether at least one of the passed items exists, once
+// normalized.
+func (s *setNormalized) HasAny(items ...string) bool {
+	return s.set.HasAny(s.normalized(items)...)
+}
+
+// IsEqual test whether s and t have the same items once normalized. The items
+// of t are normalized into a new set first, so several of them normalizing to
+// one count once.
+func (s *setNormalized) IsEqual(t Set[string]) bool {
+	u := newNonTSWithCap(0, s.normalized(t.List())...)
+	return s.set.IsEqual(u)
+}
+
+// IsSubset tests whether t is a subset of s. Unlike the other sets it can't
+// return early if t is bigger, as several items of t may normalize to one.
+func (s *setNormalized) IsSubset(t Set[string]) bool {
+	return t.Each(func(item string) bool { return s.Has(item) })
+}
+
+// Copy returns a new Set with a copy of s.
+func (s *setNormalized) Copy() Set[string] {
+	u := NewNormalized(s.normalize)
+	u.Merge(s)
+	return u
+}
+
+// Filter returns a new Set with the items of s satisfying the predicate.
+func (s *setNormalized) Filter(f func(item string) bool) Set[string] {
+	return &setNormalized{set: *s.set.Filter(f).(*set[string]), normalize: s.normalize}
+}
+
+// String returns a string representation of s
+func (s *setNormalized) String() string { return stringSet[string](s) }
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set.
+func (s *setNormalized) Merge(t Set[string]) Set[string] { return s.Add(t.List()...) }
+
+// Separate removes the set items containing in t from set s.
+func (s *setNormalized) Separate(t Set[string]) Set[string] { return s.Remove(t.List()...) }
@@ -0,0 +1,41 @@
+package set
+
+import (
+	"strings"
+	"testing"
+)
+
+func stripAccents(s string) string {
+	return strings.NewReplacer("é", "e", "è", "e", "à", "a", "ç", "c").Replace(strings.ToLower(s))
+}
+
+func TestSetNormalized(t *testing.T) {
+	s := NewNormalized(stripAccents)
+	s.Add("café", "Crème")
+
+	if !s.Has("cafe", "CAFE", "creme") {
+		t.Error("Has: normalized items should match, got", s)
+	}
+
+	s.Add("CAFÉ")
+	if s.Size() != 2 {
+		t.Error("Add: items normalized to the same string should be added once, got", s)
+	}
+
+	s.Remove("CREME")
+	if s.Size() != 1 || s.Has("crème") {
+		t.Error("Remove: normalized item should be removed, got", s)
+	}
+
+	if !s.IsEqual(newNonTS[string]("Café")) {
+		t.Error("IsEqual: normalized items should be compared, got", s)
+	}
+
+	s.Add("tea")
+	if s.IsEqual(newNonTS("cafe", "CAFE")) || Equal(s, newNonTS("cafe", "CAFE")) {
+		t.Error("IsEqual: items normalizing to one should count once, got", s)
+	}
+	if !s.IsEqual(newNonTS("cafe", "CAFÉ", "Tea")) {
+		t.Error("IsEqual: expected equal once normalized, got", s)
+	}
+}
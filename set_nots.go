@@ -1,18 +1,49 @@
 package set
 
-// Provides a common set baseline for both threadsafe and non-ts Sets.
+import "iter"
+
+// Provides a common set baseline for both threadsafe and non-ts Sets. The
+// zero value is an empty set ready to use, its map is made on the first add.
 type set[T comparable] struct {
 	m map[T]struct{} // struct{} doesn't take up space
 }
 
 var _ Set[int] = (*set[int])(nil)
 
+// Compacter is implemented by the sets created by New and NewNonTS, which can
+// manage the capacity of the map backing them. Go maps never shrink, so a
+// long-lived set which once was big keeps its memory until it's compacted.
+type Compacter interface {
+	// Grow makes room for n more items, so adding them doesn't grow the set.
+	Grow(n int)
+	// Compact releases the memory kept for the items removed from the set.
+	Compact()
+	// ClearKeepCap removes all items from the set, like Clear, but keeps the
+	// memory of the map, so refilling the set doesn't allocate it again.
+	ClearKeepCap()
+}
+
+var (
+	_ Compacter = (*set[int])(nil)
+	_ Compacter = (*setm[int])(nil)
+)
+
 // NewNonTS creates and initializes a new non-threadsafe Set.
-func newNonTS[T comparable](items ...T) Set[T] { return (&set[T]{make(map[T]struct{})}).Add(items...) }
+func newNonTS[T comparable](items ...T) Set[T] { return newNonTSWithCap(len(items), items...) }
+
+func newNonTSWithCap[T comparable](capacity int, items ...T) *set[T] {
+	s := &set[T]{make(map[T]struct{}, max(capacity, len(items)))}
+	for _, item := range items {
+		s.m[item] = null{}
+	}
+
+	return s
+}
 
 // Add includes the specified items (one or more) to the set. The underlying
 // Set s is modified. If passed nothing it silently returns.
 func (s *set[T]) Add(items ...T) Set[T] {
+	s.lazyInit()
 	for _, item := range items {
 		s.m[item] = null{}
 	}
@@ -20,6 +51,78 @@ func (s *set[T]) Add(items ...T) Set[T] {
 	return s
 }
 
+// AddSlice includes the items of the slice to the set. The map is grown to
+// fit all of them first, instead of growing repeatedly while adding.
+func (s *set[T]) AddSlice(items []T) Set[T] {
+	s.grow(len(items))
+	return s.Add(items...)
+}
+
+// AddSliceCount includes the items of the slice to the set and returns the
+// number of the new ones. The map is grown to fit all of them first.
+func (s *set[T]) AddSliceCount(items []T) int {
+	s.grow(len(items))
+	return insertCount[T](s, items)
+}
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set.
+func (s *set[T]) RemoveChanged(items ...T) bool { return removeChanged[T](s, items) }
+
+// MergeChanged merges t into s and reports whether any item was added.
+func (s *set[T]) MergeChanged(t Set[T]) bool { return mergeChanged[T](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *set[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// lazyInit makes the map of a zero value set, so items can be added to it.
+func (s *set[T]) lazyInit() {
+	if s.m == nil {
+		s.m = make(map[T]struct{})
+	}
+}
+
+// grow makes room for n more items, if they'd outnumber the current ones. Go
+// maps can't be grown in place, so the items are moved to a bigger map.
+func (s *set[T]) grow(n int) {
+	if n > len(s.m) {
+		s.rebuild(len(s.m) + n)
+	}
+}
+
+// Grow makes room for n more items. The items are moved to a new map of the
+// bigger capacity, so it takes time proportional to the size of the set.
+func (s *set[T]) Grow(n int) {
+	if n > 0 {
+		s.rebuild(len(s.m) + n)
+	}
+}
+
+// Compact moves the items to a new map of exactly their number, so the memory
+// of the old, possibly much bigger, one can be released.
+func (s *set[T]) Compact() { s.rebuild(len(s.m)) }
+
+// rebuild moves the items to a new map of the given capacity.
+func (s *set[T]) rebuild(capacity int) {
+	m := make(map[T]struct{}, capacity)
+	for item := range s.m {
+		m[item] = null{}
+	}
+	s.m = m
+}
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before.
+func (s *set[T]) Insert(item T) bool {
+	if _, ok := s.m[item]; ok {
+		return false
+	}
+	s.lazyInit()
+	s.m[item] = null{}
+
+	return true
+}
+
 // Remove deletes the specified items from the set.  The underlying Set s is
 // modified. If passed nothing it silently returns.
 func (s *set[T]) Remove(items ...T) Set[T] {
@@ -42,6 +145,10 @@ func (s *set[T]) Pop() (T, bool) {
 	return t, false
 }
 
+// PopN deletes and returns up to n items from the set. The underlying Set s is
+// modified. If set has less than n items, all of them are returned.
+func (s *set[T]) PopN(n int) []T { return popN(n, len(s.m), s.Pop) }
+
 // Has looks for the existence of items passed. It returns false if nothing is
 // passed. For multiple items it returns true only if all of  the items exist.
 func (s *set[T]) Has(items ...T) bool {
@@ -58,14 +165,27 @@ func (s *set[T]) Has(items ...T) bool {
 	return true
 }
 
+// HasAny reports whether at least one of the passed items exists.
+func (s *set[T]) HasAny(items ...T) bool {
+	for _, item := range items {
+		if _, ok := s.m[item]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *set[T]) Size() int     { return len(s.m) }
+func (s *set[T]) Len() int      { return len(s.m) }
 func (s *set[T]) Clear()        { s.m = make(map[T]struct{}) }
+func (s *set[T]) ClearKeepCap() { clear(s.m) }
 func (s *set[T]) IsEmpty() bool { return s.Size() == 0 }
 func (s *set[T]) IsEqual(t Set[T]) bool {
 	// Force locking only if given set is threadsafe.
-	if conv, ok := t.(rwLocker); ok {
+	if conv, ok := t.(lockedSet[T]); ok {
 		conv.RLock()
 		defer conv.RUnlock()
+		t = conv.unlocked()
 	}
 
 	// return false if they are no the same size
@@ -84,6 +204,10 @@ func (s *set[T]) IsEqual(t Set[T]) bool {
 
 // IsSubset tests whether t is a subset of s.
 func (s *set[T]) IsSubset(t Set[T]) bool {
+	if t.Size() > len(s.m) {
+		return false // t has an item s doesn't, no need to find it
+	}
+
 	return t.Each(func(item T) bool {
 		_, ok := s.m[item]
 		return ok
@@ -96,14 +220,17 @@ func (s *set[T]) IsSuperset(t Set[T]) bool { return t.IsSubset(s) }
 // Each traverses the items in the Set, calling the provided function for each
 // set member. Traversal will continue until all items in the Set have been
 // visited, or if the closure returns false.
-func (s *set[T]) Each(f func(item T) bool) bool {
-	for item := range s.m {
-		if !f(item) {
-			return false
+func (s *set[T]) Each(f func(item T) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set.
+func (s *set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range s.m {
+			if !yield(item) {
+				return
+			}
 		}
 	}
-
-	return true
 }
 
 // Copy returns a new Set with a copy of s.
@@ -115,11 +242,22 @@ func (s *set[T]) Copy() Set[T] {
 	return u
 }
 
+// Filter returns a new Set with the items of s satisfying the predicate.
+func (s *set[T]) Filter(f func(item T) bool) Set[T] {
+	u := newNonTS[T]()
+	for item := range s.m {
+		if f(item) {
+			u.Add(item)
+		}
+	}
+	return u
+}
+
 // String returns a string representation of s
 func (s *set[T]) String() string { return stringSet[T](s) }
 
-// List returns a slice of all items. There is also StringSlice() and
-// IntSlice() methods for returning slices of type string or int.
+// List returns a slice of all items. There are also the StringSlice and
+// IntSlice functions returning slices of type string or int.
 func (s *set[T]) List() []T {
 	list := make([]T, 0, len(s.m))
 
@@ -133,6 +271,7 @@ func (s *set[T]) List() []T {
 // Merge is like Union, however it modifies the current set it's applied on
 // with the given t set.
 func (s *set[T]) Merge(t Set[T]) Set[T] {
+	s.lazyInit()
 	t.Each(func(item T) bool {
 		s.m[item] = null{}
 		return true
@@ -141,6 +280,21 @@ func (s *set[T]) Merge(t Set[T]) Set[T] {
 	return s
 }
 
-// it's not the opposite of Merge.
-// Separate removes the set items containing in t from set s. Please aware that
+// RemoveIf deletes every item of s satisfying the predicate. The underlying
+// Set s is modified.
+func (s *set[T]) RemoveIf(f func(item T) bool) Set[T] {
+	for item := range s.m {
+		if f(item) {
+			delete(s.m, item) // deleting during range is safe for maps
+		}
+	}
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *set[T]) Retain(f func(item T) bool) Set[T] { return s.RemoveIf(not(f)) }
+
+// Separate removes the items of t from s. The underlying Set s is modified, use
+// Without to get a new set instead. Please aware that it's not the opposite
+// of Merge.
 func (s *set[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
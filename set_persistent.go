@@ -0,0 +1,506 @@
+package set
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// KV is a minimal key-value storage, which persistent sets write through to.
+// It's implemented by the user on top of any store (BoltDB, Redis, etc.).
+type KV interface {
+	// Get returns the value stored under key. If there's none, ok is false
+	// and err is nil.
+	Get(key string) (value []byte, ok bool, err error)
+	Put(key string, value []byte) error
+	Delete(key string) error
+	// Iterate calls fn for every key which starts with prefix. Iteration
+	// stops at the first error returned by fn.
+	Iterate(prefix string, fn func(key string, value []byte) error) error
+}
+
+// PersistentSet is implemented by the sets created by NewPersistent. The
+// methods of Set can't return the errors of the store, so a failed write
+// leaves the set unmodified and is kept to be returned by Err. AddErr and
+// RemoveErr return the error right away instead.
+type PersistentSet[T any] interface {
+	Set[T]
+	// AddErr is like Add, but returns the error of writing to the store. If
+	// it fails, none of the items is added.
+	AddErr(items ...T) error
+	// RemoveErr is like Remove, but returns the error of writing to the
+	// store. If it fails, none of the items is removed.
+	RemoveErr(items ...T) error
+	// Err returns the first error of the store met by the methods of Set
+	// since the previous call of Err, and resets it.
+	Err() error
+}
+
+var _ PersistentSet[int] = (*setPersistent[int])(nil)
+
+// setPersistent is a thread safe set, which writes every mutation through to
+// a key-value store.
+type setPersistent[T comparable] struct {
+	setm[T]
+	mu sync.Mutex // serializes the writes to the store
+
+	store  KV
+	prefix string
+	err    error // the first failed write, guarded by mu
+}
+
+// NewPersistent creates a new thread safe Set, which is backed by the given
+// key-value store. Every item is kept under the prefix followed by its JSON
+// encoding, so T must be serializable with encoding/json. Items already
+// stored under the prefix are loaded on construction.
+//
+// Every mutation is written to the store first, and applied to the set only
+// once the store succeeded. If a write fails, the items already written by the
+// same call are reverted on a best-effort basis, and the set is left
+// unmodified. The returned set implements PersistentSet, whose methods report
+// such failures. Copy returns an in-memory set, which isn't persisted.
+func NewPersistent[T comparable](store KV, prefix string) (Set[T], error) {
+	s := &setPersistent[T]{
+		setm:   setm[T]{set: set[T]{make(map[T]struct{})}},
+		store:  store,
+		prefix: prefix,
+	}
+
+	err := store.Iterate(prefix, func(key string, value []byte) error {
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		var item T
+		if err := json.Unmarshal(value, &item); err != nil {
+			return fmt.Errorf("decoding %q: %w", key, err)
+		}
+		s.m[item] = null{}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading set: %w", err)
+	}
+
+	return s, nil
+}
+
+func (s *setPersistent[T]) key(item T) (string, []byte, error) {
+	value, err := json.Marshal(item)
+	if err != nil {
+		return "", nil, fmt.Errorf("encoding %v: %w", item, err)
+	}
+
+	return s.prefix + string(value), value, nil
+}
+
+// put writes the items to the store. If a write fails, the items written
+// before are deleted again and the error is returned.
+func (s *setPersistent[T]) put(items ...T) error {
+	for i, item := range items {
+		key, value, err := s.key(item)
+		if err == nil {
+			if err = s.store.Put(key, value); err != nil {
+				err = fmt.Errorf("storing %q: %w", key, err)
+			}
+		}
+		if err != nil {
+			for _, written := range items[:i] {
+				key, _, _ := s.key(written)
+				_ = s.store.Delete(key) // best effort, the write error is reported
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// delete deletes the items from the store. If a deletion fails, the items
+// deleted before are written again and the error is returned.
+func (s *setPersistent[T]) delete(items ...T) error {
+	for i, item := range items {
+		key, _, err := s.key(item)
+		if err == nil {
+			if err = s.store.Delete(key); err != nil {
+				err = fmt.Errorf("deleting %q: %w", key, err)
+			}
+		}
+		if err != nil {
+			for _, deleted := range items[:i] {
+				key, value, _ := s.key(deleted)
+				_ = s.store.Put(key, value) // best effort, the delete error is reported
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failed keeps err to be returned by Err, unless an earlier error is kept
+// already, and reports whether err isn't nil. It must be called with s.mu
+// held.
+func (s *setPersistent[T]) failed(err error) bool {
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+
+	return err != nil
+}
+
+// Err returns the first error of the store met since the previous call, and
+// resets it.
+func (s *setPersistent[T]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.err
+	s.err = nil
+
+	return err
+}
+
+// missing returns the distinct items which aren't in the set, so only they are
+// written to the store. It must be called with s.mu held, so the set can't
+// change before they are added.
+func (s *setPersistent[T]) missing(items []T) []T {
+	s.setm.RLock()
+	defer s.setm.RUnlock()
+
+	var found []T
+	seen := make(map[T]struct{}, len(items))
+	for _, item := range items {
+		if _, ok := s.m[item]; ok {
+			continue
+		}
+		if _, ok := seen[item]; !ok {
+			seen[item] = null{}
+			found = append(found, item)
+		}
+	}
+
+	return found
+}
+
+// present returns the distinct items which are in the set, so only they are
+// deleted from the store. It must be called with s.mu held.
+func (s *setPersistent[T]) present(items []T) []T {
+	s.setm.RLock()
+	defer s.setm.RUnlock()
+
+	var found []T
+	seen := make(map[T]struct{}, len(items))
+	for _, item := range items {
+		if _, ok := s.m[item]; !ok {
+			continue
+		}
+		if _, ok := seen[item]; !ok {
+			seen[item] = null{}
+			found = append(found, item)
+		}
+	}
+
+	return found
+}
+
+// first returns up to n items of the set, without removing them. It must be
+// called with s.mu held.
+func (s *setPersistent[T]) first(n int) []T {
+	s.setm.RLock()
+	defer s.setm.RUnlock()
+
+	items := make([]T, 0, min(n, len(s.m)))
+	for item := range s.m {
+		if len(items) >= n {
+			break
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// Add includes the specified items (one or more) to the set. The underlying
+// Set s and the store are modified. If passed nothing it silently returns.
+func (s *setPersistent[T]) Add(items ...T) Set[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items = s.missing(items)
+	if s.failed(s.put(items...)) {
+		return s
+	}
+	s.setm.Add(items...)
+
+	return s
+}
+
+// AddErr is like Add, but returns the error of writing to the store instead of
+// keeping it for Err.
+func (s *setPersistent[T]) AddErr(items ...T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items = s.missing(items)
+	if err := s.put(items...); err != nil {
+		return err
+	}
+	s.setm.Add(items...)
+
+	return nil
+}
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before. A new item is written through to the store.
+func (s *setPersistent[T]) Insert(item T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.setm.Has(item) || s.failed(s.put(item)) {
+		return false
+	}
+
+	return s.setm.Insert(item)
+}
+
+// AddSliceCount includes the items of the slice to the set and the store, and
+// returns the number of the new ones. Only the new ones are written.
+func (s *setPersistent[T]) AddSliceCount(items []T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items = s.missing(items)
+	if s.failed(s.put(items...)) {
+		return 0
+	}
+
+	return s.setm.AddSliceCount(items)
+}
+
+// AddSlice includes the items of the slice to the set.
+func (s *setPersistent[T]) AddSlice(items []T) Set[T] { return s.Add(items...) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setPersistent[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// AddAndSize includes the specified items to the set and the store, and
+// returns the size of the set right after.
+func (s *setPersistent[T]) AddAndSize(items ...T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items = s.missing(items)
+	if s.failed(s.put(items...)) {
+		return s.setm.Size()
+	}
+
+	return s.setm.AddAndSize(items...)
+}
+
+// Remove deletes the specified items from the set. The underlying Set s and
+// the store are modified. If passed nothing it silently returns.
+func (s *setPersistent[T]) Remove(items ...T) Set[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items = s.present(items)
+	if s.failed(s.delete(items...)) {
+		return s
+	}
+	s.setm.Remove(items...)
+
+	return s
+}
+
+// RemoveErr is like Remove, but returns the error of writing to the store
+// instead of keeping it for Err.
+func (s *setPersistent[T]) RemoveErr(items ...T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items = s.present(items)
+	if err := s.delete(items...); err != nil {
+		return err
+	}
+	s.setm.Remove(items...)
+
+	return nil
+}
+
+// RemoveChanged deletes the items from the set and the store, and reports
+// whether any of them was in the set.
+func (s *setPersistent[T]) RemoveChanged(items ...T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items = s.present(items)
+	if s.failed(s.delete(items...)) {
+		return false
+	}
+
+	return s.setm.RemoveChanged(items...)
+}
+
+// MergeChanged merges t into the set and the store, and reports whether any
+// item was added.
+func (s *setPersistent[T]) MergeChanged(t Set[T]) bool {
+	return s.AddSliceCount(t.List()) != 0
+}
+
+// RemoveIf deletes every item of s satisfying the predicate from the set and
+// the store.
+func (s *setPersistent[T]) RemoveIf(f func(item T) bool) Set[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matched := s.setm.Filter(f).List()
+	if s.failed(s.delete(matched...)) {
+		return s
+	}
+	s.setm.Remove(matched...)
+
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate from the set and
+// the store.
+func (s *setPersistent[T]) Retain(f func(item T) bool) Set[T] { return s.RemoveIf(not(f)) }
+
+// Pop deletes and return an item from the set. The underlying Set s and the
+// store are modified. If set is empty, false is returned.
+func (s *setPersistent[T]) Pop() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok, err := s.pop()
+	if s.failed(err) {
+		return item, false
+	}
+
+	return item, ok
+}
+
+// pop deletes an item from the store and then from the set. It must be called
+// with s.mu held.
+func (s *setPersistent[T]) pop() (T, bool, error) {
+	var t T
+	items := s.first(1)
+	if len(items) == 0 {
+		return t, false, nil
+	}
+	if err := s.delete(items[0]); err != nil {
+		return t, false, err
+	}
+	s.setm.Remove(items[0])
+
+	return items[0], true, nil
+}
+
+// PopN deletes and returns up to n items from the set and the store.
+func (s *setPersistent[T]) PopN(n int) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.first(n)
+	if s.failed(s.delete(items...)) {
+		return []T{}
+	}
+	s.setm.Remove(items...)
+
+	return items
+}
+
+// Claim removes the item from the set and the store, and reports whether it
+// was in the set.
+func (s *setPersistent[T]) Claim(item T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.setm.Has(item) || s.failed(s.delete(item)) {
+		return false
+	}
+
+	return s.setm.Claim(item)
+}
+
+// PopRandom deletes and returns a random item from the set and the store. The
+// item is chosen like the PopRandom of the sets created by New does.
+func (s *setPersistent[T]) PopRandom(r *rand.Rand) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setm.RLock()
+	n := len(s.m)
+	var item T
+	if n != 0 {
+		item = pickRandom(s.set.All(), n, r)
+	}
+	s.setm.RUnlock()
+
+	if n == 0 || s.failed(s.delete(item)) {
+		var t T
+		return t, false
+	}
+	s.setm.Remove(item)
+
+	return item, true
+}
+
+// PopWait deletes and returns an item from the set and the store, waiting for
+// one to be added if the set is empty, or until the context is done. It
+// returns false right away if the store fails.
+func (s *setPersistent[T]) PopWait(ctx context.Context) (T, bool) {
+	for {
+		s.mu.Lock()
+		item, ok, err := s.pop()
+		if ok || s.failed(err) {
+			s.mu.Unlock()
+			return item, ok
+		}
+		// no item can be added while s.mu is held, so no wakeup is missed
+		s.setm.Lock()
+		wake := s.setm.waitAdded()
+		s.setm.Unlock()
+		s.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			var t T
+			return t, false
+		}
+	}
+}
+
+// Clear removes all items from the set and the store.
+func (s *setPersistent[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.failed(s.delete(s.setm.List()...)) {
+		s.setm.Clear()
+	}
+}
+
+// ClearKeepCap removes all items from the set and the store, keeping the
+// memory of the map.
+func (s *setPersistent[T]) ClearKeepCap() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.failed(s.delete(s.setm.List()...)) {
+		s.setm.ClearKeepCap()
+	}
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set.
+func (s *setPersistent[T]) Merge(t Set[T]) Set[T] { return s.Add(t.List()...) }
+
+// Separate removes the set items containing in t from set s.
+func (s *setPersistent[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
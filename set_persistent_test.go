@@ -0,0 +1,204 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type memoryKV struct {
+	mu   sync.Mutex
+	data map[string][]byte
+
+	puts     int
+	failAt   int // the number of the write to fail, if not zero
+	failures int
+}
+
+// fail reports whether the current write is the one to fail. It must be
+// called with kv.mu held.
+func (kv *memoryKV) fail() bool {
+	if kv.failAt == 0 {
+		return false
+	}
+	kv.failAt--
+	if kv.failAt == 0 {
+		kv.failures++
+		return true
+	}
+	return false
+}
+
+func (kv *memoryKV) Get(key string) ([]byte, bool, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	value, ok := kv.data[key]
+	return value, ok, nil
+}
+
+func (kv *memoryKV) Put(key string, value []byte) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	if kv.fail() {
+		return errors.New("put failed")
+	}
+	kv.puts++
+	kv.data[key] = value
+	return nil
+}
+
+func (kv *memoryKV) Delete(key string) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	if kv.fail() {
+		return errors.New("delete failed")
+	}
+	delete(kv.data, key)
+	return nil
+}
+
+func (kv *memoryKV) Iterate(prefix string, fn func(string, []byte) error) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	for key, value := range kv.data {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSetPersistent(t *testing.T) {
+	kv := &memoryKV{data: map[string][]byte{"other/1": []byte("1")}}
+
+	s, err := NewPersistent[int](kv, "ids/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsEmpty() {
+		t.Error("NewPersistent: keys of another prefix should not be loaded, got", s)
+	}
+
+	s.Add(1, 2, 3)
+	s.Remove(2)
+	s.Merge(newNonTS[int](4))
+
+	if _, ok, _ := kv.Get("ids/1"); !ok {
+		t.Error("Add: item should be written through to the store")
+	}
+	if _, ok, _ := kv.Get("ids/2"); ok {
+		t.Error("Remove: item should be deleted from the store")
+	}
+
+	reloaded, err := NewPersistent[int](kv, "ids/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.IsEqual(newNonTS[int](1, 3, 4)) {
+		t.Error("NewPersistent: reloaded set should have the stored items, got", reloaded)
+	}
+
+	if popped := s.PopN(2); len(popped) != 2 {
+		t.Error("PopN: expected two items, got", popped)
+	} else if reloaded, _ = NewPersistent[int](kv, "ids/"); reloaded.Size() != 1 || reloaded.Has(popped...) {
+		t.Error("PopN: popped items should be deleted from the store, got", reloaded)
+	}
+
+	s.Add(5)
+	if !s.(Claimer[int]).Claim(5) || s.(Claimer[int]).Claim(5) {
+		t.Error("Claim: item should be claimed exactly once")
+	}
+	if _, ok, _ := kv.Get("ids/5"); ok {
+		t.Error("Claim: claimed item should be deleted from the store")
+	}
+
+	s.Add(6)
+	if item, ok := s.(RandomPopper[int]).PopRandom(rand.New(rand.NewSource(1))); !ok {
+		t.Error("PopRandom: expected an item")
+	} else if _, ok, _ := kv.Get("ids/" + strconv.Itoa(item)); ok {
+		t.Error("PopRandom: popped item should be deleted from the store")
+	}
+
+	s.Clear()
+	if reloaded, _ = NewPersistent[int](kv, "ids/"); !reloaded.IsEmpty() {
+		t.Error("Clear: items should be deleted from the store, got", reloaded)
+	}
+
+	if n := s.AddSliceCount([]int{7, 8, 8}); n != 2 {
+		t.Error("AddSliceCount: expected two new items, got", n)
+	} else if reloaded, _ = NewPersistent[int](kv, "ids/"); !reloaded.Has(7, 8) {
+		t.Error("AddSliceCount: items should be written to the store, got", reloaded)
+	}
+	if s.RemoveChanged(9) || !s.RemoveChanged(8) || s.MergeChanged(newNonTS(7)) || !s.MergeChanged(newNonTS(9)) {
+		t.Error("RemoveChanged: unexpected result for a persistent set, got", s)
+	} else if reloaded, _ = NewPersistent[int](kv, "ids/"); !reloaded.IsEqual(newNonTS(7, 9)) {
+		t.Error("RemoveChanged: changes should be written to the store, got", reloaded)
+	}
+	s.(Compacter).ClearKeepCap()
+	if reloaded, _ = NewPersistent[int](kv, "ids/"); !reloaded.IsEmpty() || !s.IsEmpty() {
+		t.Error("ClearKeepCap: items should be deleted from the store, got", reloaded)
+	}
+	if len(kv.data) != 1 {
+		t.Error("Clear: keys of another prefix should stay intact")
+	}
+}
+
+func TestSetPersistent_storeFirst(t *testing.T) {
+	kv := &memoryKV{data: map[string][]byte{}}
+	s, err := NewPersistent[int](kv, "ids/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Add(1, 2)
+	kv.puts = 0
+	if n := s.AddSliceCount([]int{1, 2, 3, 3}); n != 1 || kv.puts != 1 {
+		t.Errorf("AddSliceCount: only the new item should be written, got %d new and %d writes", n, kv.puts)
+	}
+
+	p := s.(PersistentSet[int])
+	kv.failAt = 2 // the second write fails, the first is reverted
+	if err := p.AddErr(4, 5); err == nil || !strings.Contains(err.Error(), "put failed") {
+		t.Error("AddErr: expected the error of the store, got", err)
+	}
+	kv.failAt = 2
+	if err := p.RemoveErr(1, 2); err == nil {
+		t.Error("RemoveErr: expected the error of the store")
+	}
+	if err := p.Err(); err != nil {
+		t.Error("Err: errors returned by AddErr and RemoveErr should not be kept, got", err)
+	}
+
+	kv.failAt = 1
+	if item, ok := s.Pop(); ok {
+		t.Error("Pop: expected a failed write to pop nothing, got", item)
+	}
+	kv.failAt = 1
+	if _, ok := s.(PopWaiter[int]).PopWait(context.Background()); ok {
+		t.Error("PopWait: expected a failed write to pop nothing")
+	}
+	if err := p.Err(); err == nil || !strings.Contains(err.Error(), "delete failed") {
+		t.Error("Err: expected the error of the failed Pop, got", err)
+	}
+	if err := p.Err(); err != nil {
+		t.Error("Err: expected the error to be reset, got", err)
+	}
+
+	if kv.failures != 4 {
+		t.Error("memoryKV: expected four failed writes, got", kv.failures)
+	}
+	if reloaded, _ := NewPersistent[int](kv, "ids/"); !s.IsEqual(newNonTS(1, 2, 3)) || !reloaded.IsEqual(s) {
+		t.Errorf("Add: failed writes should leave the set and the store intact, got %v and %v", s, reloaded)
+	}
+}
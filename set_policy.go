@@ -0,0 +1,222 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+// IterationPolicy defines the order, in which Each, All, List and String
+// traverse the items of a set created by NewWithPolicy.
+type IterationPolicy int
+
+const (
+	// Random traverses the items in no particular order, like the sets
+	// created by New do. It has no memory overhead.
+	Random IterationPolicy = iota
+	// Insertion traverses the items in the order they were added. The set
+	// keeps an additional slice of its items, roughly doubling its memory.
+	Insertion
+	// Sorted traverses the items sorted ascending. It requires T to be of an
+	// ordered kind: an integer, a float or a string. The sorted items are
+	// kept in a slice until the set changes, so the set implements
+	// UnsafeLister, and traversals of an unchanged set don't sort again.
+	Sorted
+)
+
+// NewWithPolicy creates and initializes a new non-threadsafe Set, which
+// traverses its items in the order defined by the policy. It panics if the
+// policy is Sorted and T isn't of an ordered kind.
+func NewWithPolicy[T comparable](policy IterationPolicy) Set[T] {
+	switch policy {
+	case Random:
+		return newNonTS[T]()
+	case Insertion:
+		return NewOrdered[T]()
+	case Sorted:
+		var t T
+		if !isOrderedKind(reflect.TypeOf(t)) {
+			panic(fmt.Sprintf("set: Sorted policy requires an ordered type, got %T", t))
+		}
+		return &setSorted[T]{set: set[T]{make(map[T]struct{})}}
+	default:
+		panic(fmt.Sprintf("set: unknown iteration policy %d", policy))
+	}
+}
+
+func isOrderedKind(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// setSorted is a non-threadsafe set, which traverses its items sorted
+// ascending.
+type setSorted[T comparable] struct {
+	set[T]
+	sorted []T // the items sorted, nil once they change
+}
+
+var _ interface {
+	Set[int]
+	UnsafeLister[int]
+} = (*setSorted[int])(nil)
+
+// Add includes the specified items (one or more) to the set. The underlying
+// Set s is modified. If passed nothing it silently returns.
+func (s *setSorted[T]) Add(items ...T) Set[T] {
+	s.sorted = nil
+	s.set.Add(items...)
+	return s
+}
+
+// AddSlice includes the items of the slice to the set.
+func (s *setSorted[T]) AddSlice(items []T) Set[T] {
+	s.sorted = nil
+	s.set.AddSlice(items)
+	return s
+}
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before.
+func (s *setSorted[T]) Insert(item T) bool {
+	s.sorted = nil
+	return s.set.Insert(item)
+}
+
+// AddSliceCount includes the items of the slice to the set and returns the
+// number of the new ones.
+func (s *setSorted[T]) AddSliceCount(items []T) int {
+	s.sorted = nil
+	return s.set.AddSliceCount(items)
+}
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set.
+func (s *setSorted[T]) RemoveChanged(items ...T) bool { return removeChanged[T](s, items) }
+
+// MergeChanged merges t into s and reports whether any item was added.
+func (s *setSorted[T]) MergeChanged(t Set[T]) bool { return mergeChanged[T](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setSorted[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// Remove deletes the specified items from the set.  The underlying Set s is
+// modified. If passed nothing it silently returns.
+func (s *setSorted[T]) Remove(items ...T) Set[T] {
+	s.sorted = nil
+	s.set.Remove(items...)
+	return s
+}
+
+// Pop deletes and returns an item from the set. If set is empty, false is
+// returned.
+func (s *setSorted[T]) Pop() (T, bool) {
+	s.sorted = nil
+	return s.set.Pop()
+}
+
+// PopN deletes and returns up to n items from the set.
+func (s *setSorted[T]) PopN(n int) []T {
+	s.sorted = nil
+	return s.set.PopN(n)
+}
+
+// Clear removes all items from the set.
+func (s *setSorted[T]) Clear() {
+	s.sorted = nil
+	s.set.Clear()
+}
+
+// ClearKeepCap removes all items from the set, keeping the memory of the map.
+func (s *setSorted[T]) ClearKeepCap() {
+	s.sorted = nil
+	s.set.ClearKeepCap()
+}
+
+// RemoveIf deletes every item of s satisfying the predicate.
+func (s *setSorted[T]) RemoveIf(f func(item T) bool) Set[T] {
+	s.sorted = nil
+	s.set.RemoveIf(f)
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *setSorted[T]) Retain(f func(item T) bool) Set[T] {
+	s.sorted = nil
+	s.set.Retain(f)
+	return s
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set.
+func (s *setSorted[T]) Merge(t Set[T]) Set[T] {
+	s.sorted = nil
+	s.set.Merge(t)
+	return s
+}
+
+// Separate removes the set items containing in t from set s.
+func (s *setSorted[T]) Separate(t Set[T]) Set[T] {
+	s.sorted = nil
+	s.set.Separate(t)
+	return s
+}
+
+// UnmarshalJSON replaces the items of the set with the items of a JSON array.
+func (s *setSorted[T]) UnmarshalJSON(data []byte) error {
+	s.sorted = nil
+	return s.set.UnmarshalJSON(data)
+}
+
+// GobDecode replaces the items of the set with the gob-encoded ones.
+func (s *setSorted[T]) GobDecode(data []byte) error {
+	s.sorted = nil
+	return s.set.GobDecode(data)
+}
+
+// Each traverses the items in the Set sorted ascending, calling the provided
+// function for each set member. Traversal will continue until all items in
+// the Set have been visited, or if the closure returns false.
+func (s *setSorted[T]) Each(f func(item T) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set sorted ascending. The set
+// may be modified during the iteration, which goes on over the items it had
+// when the iteration started.
+func (s *setSorted[T]) All() iter.Seq[T] { return slices.Values(s.ListUnsafe()) }
+
+// List returns a slice of all items sorted ascending.
+func (s *setSorted[T]) List() []T { return slices.Clone(s.ListUnsafe()) }
+
+// ListUnsafe returns the items sorted ascending without copying them. They are
+// sorted once and kept until the set changes. The returned slice is shared
+// with the set: it must not be modified.
+func (s *setSorted[T]) ListUnsafe() []T {
+	if s.sorted == nil {
+		s.sorted = s.set.List()
+		slices.SortFunc(s.sorted, func(a, b T) int { return compareItems(a, b, nil, nil) })
+	}
+
+	return s.sorted[:len(s.sorted):len(s.sorted)]
+}
+
+// String returns a string representation of s
+func (s *setSorted[T]) String() string { return stringSet[T](s) }
+
+// Copy returns a new Set with a copy of s.
+func (s *setSorted[T]) Copy() Set[T] { return &setSorted[T]{set: *s.set.Copy().(*set[T])} }
+
+// Filter returns a new Set with the items of s satisfying the predicate.
+func (s *setSorted[T]) Filter(f func(item T) bool) Set[T] {
+	return &setSorted[T]{set: *s.set.Filter(f).(*set[T])}
+}
@@ -0,0 +1,90 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_NewWithPolicy(t *testing.T) {
+	items := []int{3, 1, 4, 5, 9, 2, 6}
+
+	insertion := NewWithPolicy[int](Insertion).Add(items...)
+	if l := insertion.List(); !reflect.DeepEqual(l, items) {
+		t.Error("NewWithPolicy: Insertion should list in the order of adding, got", l)
+	}
+
+	sorted := NewWithPolicy[int](Sorted).Add(items...)
+	if l := sorted.List(); !reflect.DeepEqual(l, []int{1, 2, 3, 4, 5, 6, 9}) {
+		t.Error("NewWithPolicy: Sorted should list ascending, got", l)
+	}
+	if s := sorted.String(); s != "set[1, 2, 3, 4, 5, 6, 9]" {
+		t.Error("NewWithPolicy: Sorted should print ascending, got", s)
+	}
+	var visited []int
+	sorted.Filter(func(item int) bool { return item > 3 }).Each(func(item int) bool {
+		visited = append(visited, item)
+		return true
+	})
+	if !reflect.DeepEqual(visited, []int{4, 5, 6, 9}) {
+		t.Error("NewWithPolicy: Sorted filtered set should traverse ascending, got", visited)
+	}
+
+	random := NewWithPolicy[int](Random).Add(items...)
+	if random.Size() != len(items) || !random.Has(items...) {
+		t.Error("NewWithPolicy: Random should hold all the items, got", random)
+	}
+}
+
+func Test_NewWithPolicy_unordered(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewWithPolicy: Sorted should panic for an unordered type")
+		}
+	}()
+
+	NewWithPolicy[struct{ a int }](Sorted)
+}
+
+func Test_NewWithPolicy_ListUnsafe(t *testing.T) {
+	s := NewWithPolicy[int](Sorted).Add(3, 1, 2)
+	lister := s.(UnsafeLister[int])
+
+	if l := lister.ListUnsafe(); !reflect.DeepEqual(l, []int{1, 2, 3}) {
+		t.Error("ListUnsafe: expected the items sorted ascending, got", l)
+	}
+
+	s.Add(0)
+	s.Remove(2)
+	if l := lister.ListUnsafe(); !reflect.DeepEqual(l, []int{0, 1, 3}) {
+		t.Error("ListUnsafe: expected the changed items sorted ascending, got", l)
+	}
+	if item, _ := s.Pop(); !reflect.DeepEqual(lister.ListUnsafe(), s.List()) || s.Has(item) {
+		t.Error("ListUnsafe: expected the items left after Pop, got", lister.ListUnsafe())
+	}
+
+	_ = append(lister.ListUnsafe(), 42)
+	if s.Has(42) || s.Size() != 2 {
+		t.Error("ListUnsafe: appending to the result should not modify the set, got", s)
+	}
+}
+
+func benchmarkSortedList(b *testing.B, list func(Set[int]) []int) {
+	s := NewWithPolicy[int](Sorted)
+	for i := 0; i < 1000; i++ {
+		s.Add(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list(s)
+	}
+}
+
+func BenchmarkSortedList(b *testing.B) {
+	benchmarkSortedList(b, func(s Set[int]) []int { return s.List() })
+}
+
+func BenchmarkSortedListUnsafe(b *testing.B) {
+	benchmarkSortedList(b, func(s Set[int]) []int { return s.(UnsafeLister[int]).ListUnsafe() })
+}
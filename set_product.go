@@ -0,0 +1,67 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Pair is an ordered pair of items, an element of a cartesian product.
+type Pair[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// Product returns a new non-threadsafe set of all pairs of the items of a and
+// b, which has a.Size() * b.Size() items. Use ProductSeq to iterate the pairs
+// without building the set.
+func Product[A, B comparable](a Set[A], b Set[B]) Set[Pair[A, B]] {
+	u := newNonTSWithCap[Pair[A, B]](a.Size() * b.Size())
+	for first, second := range ProductSeq(a, b) {
+		u.m[Pair[A, B]{first, second}] = null{}
+	}
+
+	return u
+}
+
+// ProductSeq returns an iterator over the cartesian product of a and b,
+// yielding every pair of their items lazily, without building the product.
+// Only the items of b are snapshotted, so memory stays bounded by b's size.
+func ProductSeq[A, B comparable](a Set[A], b Set[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		right := b.List()
+		for left := range a.All() {
+			for _, item := range right {
+				if !yield(left, item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MaxPowersetSize is the largest size of a set Powerset accepts.
+const MaxPowersetSize = 20
+
+// Powerset returns all 2^n subsets of s, including the empty set and the copy
+// of s. Every subset is an independent set of the same kind as s. The number
+// of subsets grows exponentially, so Powerset panics if s has more than
+// MaxPowersetSize items, rather than exhausting the memory.
+func Powerset[T comparable](s Set[T]) []Set[T] {
+	items := s.List()
+	if len(items) > MaxPowersetSize {
+		panic(fmt.Sprintf("set: Powerset of %d items exceeds the limit of %d", len(items), MaxPowersetSize))
+	}
+
+	subsets := make([]Set[T], 0, 1<<len(items))
+	for mask := 0; mask < 1<<len(items); mask++ {
+		subset := emptyLike(s)
+		for i, item := range items {
+			if mask&(1<<i) != 0 {
+				subset.Add(item)
+			}
+		}
+		subsets = append(subsets, subset)
+	}
+
+	return subsets
+}
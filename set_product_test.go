@@ -0,0 +1,101 @@
+package set
+
+import "testing"
+
+func Test_ProductSeq(t *testing.T) {
+	type pair struct {
+		a int
+		b string
+	}
+
+	a := newTS(1, 2)
+	b := newNonTS("x", "y", "z")
+
+	eager := newNonTS[pair]()
+	a.Each(func(left int) bool {
+		b.Each(func(right string) bool {
+			eager.Add(pair{left, right})
+			return true
+		})
+		return true
+	})
+
+	lazy := newNonTS[pair]()
+	for left, right := range ProductSeq(a, b) {
+		lazy.Add(pair{left, right})
+	}
+
+	if lazy.Size() != 6 || !lazy.IsEqual(eager) {
+		t.Errorf("ProductSeq: expected %v, got %v", eager, lazy)
+	}
+
+	n := 0
+	for range ProductSeq(a, b) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Error("ProductSeq: should stop after break, visited", n)
+	}
+}
+
+func Test_Product(t *testing.T) {
+	p := Product(newTS(1, 2), newNonTS("x", "y", "z"))
+
+	if p.Size() != 6 {
+		t.Error("Product: expected six pairs, got", p.Size())
+	}
+	if !p.Has(Pair[int, string]{1, "x"}, Pair[int, string]{2, "z"}) {
+		t.Error("Product: expected pairs are missing, got", p)
+	}
+	if p.Has(Pair[int, string]{3, "x"}) {
+		t.Error("Product: unexpected pair found in", p)
+	}
+
+	if p = Product(newTS(1, 2), newNonTS[string]()); !p.IsEmpty() {
+		t.Error("Product: product with an empty set should be empty, got", p)
+	}
+}
+
+func Test_Powerset(t *testing.T) {
+	subsets := Powerset(newNonTS(1, 2, 3))
+	if len(subsets) != 8 {
+		t.Fatal("Powerset: expected eight subsets, got", len(subsets))
+	}
+
+	for _, want := range []Set[int]{
+		newNonTS[int](),
+		newNonTS(1), newNonTS(2), newNonTS(3),
+		newNonTS(1, 2), newNonTS(1, 3), newNonTS(2, 3),
+		newNonTS(1, 2, 3),
+	} {
+		found := 0
+		for _, subset := range subsets {
+			if subset.IsEqual(want) {
+				found++
+			}
+		}
+		if found != 1 {
+			t.Errorf("Powerset: expected the subset %v exactly once, found %d", want, found)
+		}
+	}
+
+	subsets[0].Add(4)
+	if subsets[1].Has(4) {
+		t.Error("Powerset: subsets should be independent")
+	}
+}
+
+func Test_Powerset_limit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Powerset: should panic for a set above the limit")
+		}
+	}()
+
+	s := newNonTS[int]()
+	for i := 0; i <= MaxPowersetSize; i++ {
+		s.Add(i)
+	}
+	Powerset(s)
+}
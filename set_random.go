@@ -0,0 +1,91 @@
+package set
+
+import (
+	"iter"
+	"math/rand"
+)
+
+// RandomPopper is implemented by threadsafe sets, which can pop a random item,
+// e.g. for fair sampling.
+type RandomPopper[T any] interface {
+	// PopRandom deletes and returns an item chosen uniformly at random by r.
+	// If the set is empty, false is returned.
+	PopRandom(r *rand.Rand) (T, bool)
+}
+
+var _ RandomPopper[int] = (*setm[int])(nil)
+
+// PopRandom deletes and returns an item chosen uniformly at random by r. The
+// item is picked by a random position in the iteration order of the map,
+// under the write lock, so it takes O(n) time per pop without allocating. The
+// iteration order of a map differs between runs, so the same seed doesn't
+// pop the same items.
+func (s *setm[T]) PopRandom(r *rand.Rand) (T, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.m) == 0 {
+		var t T
+		return t, false
+	}
+	item := pickRandom(s.set.All(), len(s.m), r)
+	s.remove(item)
+
+	return item, true
+}
+
+// PopRandom deletes and returns an item chosen uniformly at random by r, like
+// the one of New does.
+func (s *setAnym[T]) PopRandom(r *rand.Rand) (T, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.setAny.IsEmpty() {
+		var t T
+		return t, false
+	}
+	item := pickRandom(s.setAny.All(), s.setAny.size, r)
+	s.setAny.Remove(item)
+
+	return item, true
+}
+
+// pickRandom returns the item at a position chosen by r among the n items. A
+// uniformly random position makes a uniformly random item, whatever order the
+// items come in.
+func pickRandom[T any](items iter.Seq[T], n int, r *rand.Rand) T {
+	k := r.Intn(n)
+	for item := range items {
+		if k == 0 {
+			return item
+		}
+		k--
+	}
+
+	panic("set: fewer items than the size of the set")
+}
+
+// Sample returns a new set of the same kind as s with up to n items of s,
+// chosen at random by r. Unlike PopRandom, s isn't modified, and it's
+// traversed once with reservoir sampling, so the items aren't collected into
+// a slice first. The order of traversal is arbitrary, so the same seed doesn't
+// guarantee the same sample. If n >= s.Size(), a copy of s is returned.
+func Sample[T comparable](s Set[T], n int, r *rand.Rand) Set[T] {
+	if n >= s.Size() {
+		return s.Copy()
+	}
+
+	reservoir := make([]T, 0, max(n, 0))
+	i := 0
+	s.Each(func(item T) bool {
+		if len(reservoir) < n {
+			reservoir = append(reservoir, item)
+		} else if j := r.Intn(i + 1); j < n {
+			reservoir[j] = item
+		}
+		i++
+		return true
+	})
+
+	return emptyLike(s).Add(reservoir...)
+}
@@ -0,0 +1,112 @@
+package set
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSet_PopRandom(t *testing.T) {
+	const (
+		items  = 10
+		trials = 20000
+	)
+
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, items)
+	for i := 0; i < trials; i++ {
+		s := newTS[int]()
+		for item := 0; item < items; item++ {
+			s.Add(item)
+		}
+
+		item, ok := s.(RandomPopper[int]).PopRandom(r)
+		if !ok || s.Has(item) || s.Size() != items-1 {
+			t.Fatal("PopRandom: expected an item to be removed, got", item, s)
+		}
+		counts[item]++
+	}
+
+	// every item is expected trials/items times, allow 10% off
+	for item, count := range counts {
+		if count < trials/items*9/10 || count > trials/items*11/10 {
+			t.Errorf("PopRandom: item %d popped %d times out of %d, distribution isn't uniform", item, count, trials)
+		}
+	}
+
+	popped := newNonTS[int]()
+	s := newTS(1, 2, 3, 4, 5)
+	for {
+		item, ok := s.(RandomPopper[int]).PopRandom(r)
+		if !ok {
+			break
+		}
+		if !popped.Insert(item) {
+			t.Error("PopRandom: item popped twice:", item)
+		}
+	}
+	if !popped.IsEqual(newNonTS(1, 2, 3, 4, 5)) || !s.IsEmpty() {
+		t.Error("PopRandom: every item should be popped once, got", popped)
+	}
+
+	h := NewAny[hashInt](1, 2)
+	if item, ok := h.(RandomPopper[hashInt]).PopRandom(r); !ok || h.Has(item) || h.Size() != 1 {
+		t.Error("PopRandom: expected an item to be removed from a hashable set, got", h)
+	}
+}
+
+func BenchmarkSet_PopRandom(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	s := newTS[int]()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if s.IsEmpty() {
+			b.StopTimer()
+			for item := 0; item < 1000; item++ {
+				s.Add(item)
+			}
+			b.StartTimer()
+		}
+		s.(RandomPopper[int]).PopRandom(r)
+	}
+}
+
+func Test_Sample(t *testing.T) {
+	s := newTS[int]()
+	for item := 0; item < 100; item++ {
+		s.Add(item)
+	}
+	r := rand.New(rand.NewSource(1))
+
+	for _, n := range []int{0, 1, 10, 99, 100, 1000} {
+		sample := Sample(s, n, r)
+		if sample.Size() != min(n, s.Size()) {
+			t.Errorf("Sample: expected %d items, got %d", min(n, s.Size()), sample.Size())
+		}
+		if !s.IsSubset(sample) {
+			t.Error("Sample: sampled items should exist in the source, got", sample)
+		}
+		if _, ok := sample.(*setm[int]); !ok {
+			t.Errorf("Sample: expected a set of the source's kind, got %T", sample)
+		}
+	}
+	if s.Size() != 100 {
+		t.Error("Sample: source should not be modified, got", s.Size())
+	}
+
+	// every item is expected to be sampled trials*n/size times, allow 10% off
+	const trials = 5000
+	counts := make(map[int]int)
+	small := newNonTS(1, 2, 3, 4, 5)
+	for i := 0; i < trials; i++ {
+		Sample(small, 2, r).Each(func(item int) bool {
+			counts[item]++
+			return true
+		})
+	}
+	for item, count := range counts {
+		if count < trials*2/5*9/10 || count > trials*2/5*11/10 {
+			t.Errorf("Sample: item %d sampled %d times out of %d, distribution isn't uniform", item, count, trials)
+		}
+	}
+}
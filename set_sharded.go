@@ -0,0 +1,340 @@
+package set
+
+import (
+	"hash/maphash"
+	"iter"
+)
+
+// setSharded is a threadsafe set, which partitions its items across several
+// shards by their hash. Every shard has its own lock, so operations on items
+// of different shards don't contend.
+type setSharded[T comparable] struct {
+	seed   maphash.Seed
+	shards []*setm[T]
+}
+
+var _ Set[int] = (*setSharded[int])(nil)
+
+// NewSharded creates a new threadsafe Set, which spreads its items across the
+// given number of shards, each guarded by its own lock. It suits sets under
+// heavy concurrent writes of different items, which would all serialize on the
+// single lock of the set created by New. Operations on the whole set, like
+// Size, List or IsEqual, lock all the shards, in a fixed order.
+func NewSharded[T comparable](shards int) Set[T] {
+	return newSharded[T](maphash.MakeSeed(), max(shards, 1))
+}
+
+func newSharded[T comparable](seed maphash.Seed, shards int) *setSharded[T] {
+	s := &setSharded[T]{seed: seed, shards: make([]*setm[T], shards)}
+	for i := range s.shards {
+		s.shards[i] = &setm[T]{set: set[T]{make(map[T]struct{})}}
+	}
+
+	return s
+}
+
+// shard returns the shard the item belongs to.
+func (s *setSharded[T]) shard(item T) *setm[T] {
+	return s.shards[hashComparable(s.seed, item)%uint64(len(s.shards))]
+}
+
+// rLockAll read-locks all the shards in order. The returned function releases
+// the locks.
+func (s *setSharded[T]) rLockAll() (unlock func()) {
+	for _, shard := range s.shards {
+		shard.RLock()
+	}
+
+	return func() {
+		for i := len(s.shards) - 1; i >= 0; i-- {
+			s.shards[i].RUnlock()
+		}
+	}
+}
+
+// Add includes the specified items (one or more) to the set. The underlying
+// Set s is modified. If passed nothing it silently returns.
+func (s *setSharded[T]) Add(items ...T) Set[T] {
+	for _, item := range items {
+		s.shard(item).Add(item)
+	}
+
+	return s
+}
+
+// AddSlice includes the items of the slice to the set.
+func (s *setSharded[T]) AddSlice(items []T) Set[T] { return s.Add(items...) }
+
+// AddSliceCount includes the items of the slice to the set and returns the
+// number of the new ones. Every item is inserted under the lock of its own
+// shard, so an item is counted by one caller only, though the items aren't
+// added at a single point in time.
+func (s *setSharded[T]) AddSliceCount(items []T) int { return insertCount[T](s, items) }
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set. Every item is checked and removed under the lock of its own
+// shard, so a concurrent removal of the same item is reported by one caller
+// only.
+func (s *setSharded[T]) RemoveChanged(items ...T) bool {
+	changed := false
+	for _, item := range items {
+		if s.shard(item).RemoveChanged(item) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// MergeChanged merges t into s and reports whether any item was added.
+func (s *setSharded[T]) MergeChanged(t Set[T]) bool { return mergeChanged[T](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setSharded[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before.
+func (s *setSharded[T]) Insert(item T) bool { return s.shard(item).Insert(item) }
+
+// Remove deletes the specified items from the set.  The underlying Set s is
+// modified. If passed nothing it silently returns.
+func (s *setSharded[T]) Remove(items ...T) Set[T] {
+	for _, item := range items {
+		s.shard(item).Remove(item)
+	}
+
+	return s
+}
+
+// Pop deletes and return an item from the set. The underlying Set s is
+// modified. If set is empty, false is returned.
+func (s *setSharded[T]) Pop() (T, bool) {
+	for _, shard := range s.shards {
+		if item, ok := shard.Pop(); ok {
+			return item, true
+		}
+	}
+
+	var t T
+
+	return t, false
+}
+
+// PopN deletes and returns up to n items from the set. The shards are drained
+// one by one, so no item is returned twice, though the items aren't taken
+// from a single point in time.
+func (s *setSharded[T]) PopN(n int) []T {
+	items := []T{}
+	for _, shard := range s.shards {
+		if len(items) >= n {
+			break
+		}
+		items = append(items, shard.PopN(n-len(items))...)
+	}
+
+	return items
+}
+
+// Has looks for the existence of items passed. It returns false if nothing is
+// passed. For multiple items it returns true only if all of  the items exist.
+func (s *setSharded[T]) Has(items ...T) bool {
+	if len(items) == 0 {
+		return false
+	}
+
+	for _, item := range items {
+		if !s.shard(item).Has(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasAny reports whether at least one of the passed items exists. Every item
+// is looked up under the lock of its own shard only.
+func (s *setSharded[T]) HasAny(items ...T) bool {
+	for _, item := range items {
+		if s.shard(item).Has(item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Size returns the number of items in a set.
+func (s *setSharded[T]) Size() int {
+	defer s.rLockAll()()
+
+	return s.size()
+}
+
+// Len is an alias of Size.
+func (s *setSharded[T]) Len() int { return s.Size() }
+
+// size returns the number of items in a set. It must be called with all the
+// shards locked.
+func (s *setSharded[T]) size() int {
+	n := 0
+	for _, shard := range s.shards {
+		n += len(shard.m)
+	}
+
+	return n
+}
+
+// Clear removes all items from the set.
+func (s *setSharded[T]) Clear() {
+	for _, shard := range s.shards {
+		shard.Lock()
+	}
+	defer func() {
+		for i := len(s.shards) - 1; i >= 0; i-- {
+			s.shards[i].Unlock()
+		}
+	}()
+
+	for _, shard := range s.shards {
+		shard.clear()
+	}
+}
+
+// IsEmpty reports whether the Set is empty.
+func (s *setSharded[T]) IsEmpty() bool { return s.Size() == 0 }
+
+// IsEqual test whether s and t are the same in size and have the same items.
+// The items of t are snapshotted first, so no lock of t is held while the
+// shards of s are, which would deadlock with t comparing itself to s. All the
+// shards of s are locked for the whole comparison.
+func (s *setSharded[T]) IsEqual(t Set[T]) bool {
+	if t == Set[T](s) {
+		return true // don't read-lock the shards twice
+	}
+
+	u := newNonTSWithCap(0, t.List()...)
+	defer s.rLockAll()()
+
+	return s.size() == len(u.m) && s.subsetOf(u)
+}
+
+// subsetOf reports whether all the items of s are in u. It must be called with
+// all the shards locked.
+func (s *setSharded[T]) subsetOf(u *set[T]) bool {
+	for _, shard := range s.shards {
+		if !shard.set.Each(func(item T) bool { _, ok := u.m[item]; return ok }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsSubset tests whether t is a subset of s. The items of t are snapshotted
+// before the shards of s are locked, like IsEqual does.
+func (s *setSharded[T]) IsSubset(t Set[T]) bool {
+	if t == Set[T](s) {
+		return true // don't read-lock the shards twice
+	}
+
+	items := t.List()
+	defer s.rLockAll()()
+
+	if len(items) > s.size() {
+		return false
+	}
+
+	for _, item := range items {
+		if _, ok := s.shard(item).m[item]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsSuperset tests whether t is a superset of s. The items of t are
+// snapshotted before the shards of s are locked, like IsEqual does.
+func (s *setSharded[T]) IsSuperset(t Set[T]) bool {
+	if t == Set[T](s) {
+		return true // don't read-lock the shards twice
+	}
+
+	u := newNonTSWithCap(0, t.List()...)
+	defer s.rLockAll()()
+
+	return s.subsetOf(u)
+}
+
+// Each traverses the items in the Set, calling the provided function for each
+// set member. Traversal will continue until all items in the Set have been
+// visited, or if the closure returns false.
+func (s *setSharded[T]) Each(f func(item T) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set. All the shards are locked
+// for the whole iteration.
+func (s *setSharded[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		defer s.rLockAll()()
+
+		for _, shard := range s.shards {
+			if !shard.set.Each(yield) {
+				return
+			}
+		}
+	}
+}
+
+// String returns a string representation of s
+func (s *setSharded[T]) String() string { return stringSet[T](s) }
+
+// List returns a slice of all items.
+func (s *setSharded[T]) List() []T {
+	defer s.rLockAll()()
+
+	items := make([]T, 0, s.size())
+	for _, shard := range s.shards {
+		for item := range shard.m {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// Copy returns a new Set with a copy of s.
+func (s *setSharded[T]) Copy() Set[T] { return s.Filter(func(T) bool { return true }) }
+
+// Filter returns a new Set with the items of s satisfying the predicate. All
+// the shards are locked for the whole filtering.
+func (s *setSharded[T]) Filter(f func(item T) bool) Set[T] {
+	defer s.rLockAll()()
+
+	u := newSharded[T](s.seed, len(s.shards))
+	for i, shard := range s.shards {
+		u.shards[i].set = *shard.set.Filter(f).(*set[T])
+	}
+
+	return u
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set. The items of t are listed before adding, so no lock
+// of t is held while the shards of s are written.
+func (s *setSharded[T]) Merge(t Set[T]) Set[T] { return s.Add(t.List()...) }
+
+// Separate removes the set items containing in t from set s.
+func (s *setSharded[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
+
+// RemoveIf deletes every item of s satisfying the predicate. Every shard is
+// processed under its own lock.
+func (s *setSharded[T]) RemoveIf(f func(item T) bool) Set[T] {
+	for _, shard := range s.shards {
+		shard.RemoveIf(f)
+	}
+
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *setSharded[T]) Retain(f func(item T) bool) Set[T] { return s.RemoveIf(not(f)) }
@@ -0,0 +1,163 @@
+package set
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetSharded(t *testing.T) {
+	s := NewSharded[int](4)
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+
+	if s.Size() != 100 || !s.Has(0, 50, 99) || s.Has(100) {
+		t.Error("NewSharded: expected the items from zero to 99, got", s)
+	}
+
+	other := newNonTS(s.List()...)
+	if !s.IsEqual(other) || !s.IsSubset(other) || !s.IsSuperset(other) {
+		t.Error("IsEqual: sharded set should be equal to the set of its items")
+	}
+
+	if !s.IsEqual(s) || !s.IsSubset(s) || !s.IsSuperset(s) {
+		t.Error("IsEqual: sharded set should be equal to itself")
+	}
+
+	c := s.Copy()
+	s.Remove(0, 1)
+	if c.Size() != 100 || !c.Has(0, 1) {
+		t.Error("Copy: copy should not change with the set, got", c.Size())
+	}
+
+	s.RemoveIf(func(item int) bool { return item%2 == 0 })
+	if s.Size() != 49 || s.Has(2) {
+		t.Error("RemoveIf: even items should be deleted, got", s.Size())
+	}
+
+	popped := s.PopN(10)
+	if len(popped) != 10 || s.Size() != 39 || s.Has(popped...) {
+		t.Error("PopN: expected ten popped items, got", popped)
+	}
+
+	s.Merge(c)
+	if !s.IsEqual(c) {
+		t.Error("Merge: set merged with its former copy should equal it")
+	}
+
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Error("Clear: set should be empty, got", s)
+	}
+}
+
+func TestSetSharded_concurrent(t *testing.T) {
+	s := NewSharded[int](8)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s.Add(g*100 + i)
+				s.Size()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if s.Size() != 1600 {
+		t.Error("Add: expected 1600 items, got", s.Size())
+	}
+}
+
+func benchmarkConcurrentAdd(b *testing.B, s Set[int]) {
+	const goroutines = 16
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				s.Add(i)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkConcurrentAdd(b *testing.B) {
+	benchmarkConcurrentAdd(b, New[int]())
+}
+
+func BenchmarkConcurrentAddSharded(b *testing.B) {
+	benchmarkConcurrentAdd(b, NewSharded[int](32))
+}
+
+func TestSetSharded_IsEqual_crossed(t *testing.T) {
+	// Compare a sharded and a threadsafe set in both directions while writers
+	// contend for their locks, like TestSet_IsEqual_crossed does.
+	// The sets are big, so the comparisons hold the locks long enough to be
+	// preempted even on a single CPU.
+	items := make([]int, 10000)
+	for i := range items {
+		items[i] = i
+	}
+	a := New[int](items...)
+	sh := NewSharded[int](4).Add(items...)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		for _, f := range []func(i int){
+			func(int) { a.IsEqual(sh) },
+			func(int) { sh.IsEqual(a) },
+			func(int) { sh.IsSubset(a) },
+			func(int) { sh.IsSuperset(a) },
+			func(i int) { a.Add(i) },
+			func(i int) { sh.Add(i) },
+		} {
+			for g := 0; g < 4; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 60; i++ {
+						f(i)
+					}
+				}()
+			}
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("IsEqual: crossed comparisons deadlocked")
+	}
+
+	if !a.IsEqual(sh) || !sh.IsEqual(a) || !sh.IsSubset(a) || !sh.IsSuperset(a) {
+		t.Error("IsEqual: sets with the same items should be equal")
+	}
+}
+
+func TestSetSharded_negativeZero(t *testing.T) {
+	type point struct{ F float64 }
+	negZero := math.Copysign(0, -1)
+
+	floats := NewSharded[float32](8).Add(0, float32(negZero))
+	if floats.Size() != 1 || !floats.Has(float32(negZero)) {
+		t.Error("Add: -0 and 0 are equal and should be added once, got", floats)
+	}
+
+	points := NewSharded[point](8).Add(point{0}, point{negZero})
+	if points.Size() != 1 || !points.Has(point{negZero}) || !points.IsEqual(New(point{0})) {
+		t.Error("Add: points with -0 and 0 fields are equal and should be added once, got", points)
+	}
+}
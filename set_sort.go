@@ -0,0 +1,194 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"container/heap"
+	"fmt"
+	"iter"
+	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SortedByScore returns the items of s sorted descending by the score computed
+// for each of them. Items with equal scores are sorted in the canonical order
+// Shuffled starts from, so ties are broken the same way on every call.
+func SortedByScore[T comparable](s Set[T], score func(T) float64) []T {
+	items := s.List()
+	sortCanonical(items)
+	scores := make(map[T]float64, len(items))
+	for _, item := range items {
+		scores[item] = score(item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return scores[items[i]] > scores[items[j]]
+	})
+
+	return items
+}
+
+// Shuffled returns the items of s in a random order determined by rng only,
+// so the same seed always produces the same order for the same set. Unlike
+// List, whose order is arbitrary, the items are first sorted in a canonical
+// order and then shuffled.
+func Shuffled[T comparable](s Set[T], rng *rand.Rand) []T {
+	items := s.List()
+	sortCanonical(items)
+	rng.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+
+	return items
+}
+
+// sortCanonical sorts the items by their value if they are of an ordered
+// kind, otherwise by their Go-syntax representation. Items of different kinds
+// are sorted by their kind.
+func sortCanonical[T any](items []T) {
+	keys := canonicalKeys(items)
+	slices.SortFunc(keys, compareCanonical[T])
+	for i, key := range keys {
+		items[i] = key.item
+	}
+}
+
+// canonicalKey holds what an item is sorted by in the canonical order, so it's
+// computed once per item rather than per comparison.
+type canonicalKey[T any] struct {
+	item    T
+	value   reflect.Value
+	encoded []byte // Go-syntax representation of items of unordered kinds
+}
+
+func canonicalKeys[T any](items []T) []canonicalKey[T] {
+	keys := make([]canonicalKey[T], len(items))
+	for i, item := range items {
+		v := reflect.ValueOf(item)
+		keys[i] = canonicalKey[T]{item: item, value: v}
+		if _, ok := compareOrdered(v, v); !ok {
+			keys[i].encoded = fmt.Appendf(nil, "%#v", item)
+		}
+	}
+
+	return keys
+}
+
+func compareCanonical[T any](a, b canonicalKey[T]) int {
+	if a.value.Kind() != b.value.Kind() {
+		return cmp.Compare(a.value.Kind(), b.value.Kind())
+	}
+	if c, ok := compareOrdered(a.value, b.value); ok {
+		return c
+	}
+
+	return bytes.Compare(a.encoded, b.encoded)
+}
+
+// SortedString returns a string representation of s, like String does, but
+// with the items sorted ascending, so the same set is always printed the same
+// way.
+func SortedString[T constraints.Ordered](s Set[T]) string { return stringItems(SortedList(s)) }
+
+// SortedList returns a slice of all items, sorted ascending. An empty set
+// gives an empty, non-nil slice. Threadsafe sets are snapshotted under a single
+// read lock.
+func SortedList[T constraints.Ordered](s Set[T]) []T {
+	items := make([]T, 0, s.Size())
+	s.Each(func(item T) bool {
+		items = append(items, item)
+		return true
+	})
+	slices.Sort(items)
+
+	return items
+}
+
+// EachSorted is like Each, but calls f for the items in ascending order, so
+// the traversal is reproducible, e.g. in tests. The items are snapshotted, like
+// SortedList does, and sorted before the first call. It reports whether all
+// the items were visited.
+func EachSorted[T constraints.Ordered](s Set[T], f func(T) bool) bool {
+	for _, item := range SortedList(s) {
+		if !f(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// OrderedSlice is a snapshot of the items of a set, which implements
+// sort.Interface in ascending order.
+type OrderedSlice[T constraints.Ordered] []T
+
+func (x OrderedSlice[T]) Len() int           { return len(x) }
+func (x OrderedSlice[T]) Less(i, j int) bool { return cmp.Less(x[i], x[j]) }
+func (x OrderedSlice[T]) Swap(i, j int)      { x[i], x[j] = x[j], x[i] }
+
+// Sortable returns a snapshot of the items of s, which can be passed to
+// sort.Sort, or other code taking a sort.Interface. Sorting it doesn't affect
+// s.
+func Sortable[T constraints.Ordered](s Set[T]) OrderedSlice[T] { return s.List() }
+
+// EqualSortedSlice reports whether s holds exactly the items of the sorted
+// slice. The items of s are sorted once and compared with the slice linearly,
+// which is cheaper than building a set from the slice. The slice must be
+// sorted ascending and have no duplicates.
+func EqualSortedSlice[T constraints.Ordered](s Set[T], sorted []T) bool {
+	return slices.Equal(SortedList(s), sorted)
+}
+
+// MergeOrdered returns an iterator yielding the union of all the given sets in
+// ascending order, without duplicates. Every set is snapshotted and sorted
+// once, then the snapshots are merged through a min-heap, so the union is
+// streamed without being built as a set.
+func MergeOrdered[T constraints.Ordered](sets ...Set[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h := make(cursorHeap[T], 0, len(sets))
+		for _, s := range sets {
+			items := s.List()
+			if len(items) == 0 {
+				continue
+			}
+			slices.Sort(items)
+			h = append(h, items)
+		}
+		heap.Init(&h)
+
+		var last T
+		for i := 0; h.Len() > 0; i++ {
+			item := h[0][0]
+			if h[0] = h[0][1:]; len(h[0]) == 0 {
+				heap.Pop(&h)
+			} else {
+				heap.Fix(&h, 0)
+			}
+
+			if i > 0 && item == last {
+				continue
+			}
+			last = item
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// cursorHeap is a min-heap of non-empty sorted slices, ordered by their first
+// item.
+type cursorHeap[T constraints.Ordered] [][]T
+
+func (h cursorHeap[T]) Len() int           { return len(h) }
+func (h cursorHeap[T]) Less(i, j int) bool { return h[i][0] < h[j][0] }
+func (h cursorHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *cursorHeap[T]) Push(x any)        { *h = append(*h, x.([]T)) }
+func (h *cursorHeap[T]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
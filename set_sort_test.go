@@ -0,0 +1,167 @@
+package set
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func Test_SortedByScore(t *testing.T) {
+	s := newNonTS[string]("go", "gopher", "set", "a")
+
+	sorted := SortedByScore(s, func(item string) float64 { return float64(len(item)) })
+	if len(sorted) != 4 {
+		t.Fatal("SortedByScore: expected four items, got", sorted)
+	}
+	if sorted[0] != "gopher" || sorted[3] != "a" {
+		t.Error("SortedByScore: items should be sorted by descending length, got", sorted)
+	}
+
+	ties := newNonTS("dd", "b", "cc", "a", "bb", "c", "aa", "d")
+	want := []string{"aa", "bb", "cc", "dd", "a", "b", "c", "d"}
+	for i := 0; i < 20; i++ {
+		if got := SortedByScore(ties, func(item string) float64 { return float64(len(item)) }); !reflect.DeepEqual(got, want) {
+			t.Fatalf("SortedByScore: items of equal score should be in the canonical order, expected %v, got %v", want, got)
+		}
+	}
+}
+
+func Test_MergeOrdered(t *testing.T) {
+	a := newTS(5, 1, 3)
+	b := newNonTS(2, 3, 4)
+	c := newNonTS(9, 1, 5)
+
+	var merged []int
+	for item := range MergeOrdered(a, b, newNonTS[int](), c) {
+		merged = append(merged, item)
+	}
+
+	if !reflect.DeepEqual(merged, []int{1, 2, 3, 4, 5, 9}) {
+		t.Error("MergeOrdered: expected sorted deduplicated union, got", merged)
+	}
+
+	for range MergeOrdered(a, b) {
+		break // stopping early must not panic
+	}
+}
+
+func Test_SortedString(t *testing.T) {
+	s := newTS(3, 10, 1, 2)
+
+	want := "set[1, 2, 3, 10]"
+	for i := 0; i < 10; i++ {
+		if got := SortedString(s); got != want {
+			t.Fatalf("SortedString: expected %q, got %q", want, got)
+		}
+	}
+
+	if got := SortedString(newNonTS[string]()); got != "set[]" {
+		t.Errorf("SortedString: expected empty set, got %q", got)
+	}
+}
+
+func Test_Shuffled(t *testing.T) {
+	s := newTS(1, 2, 3, 4, 5, 6, 7, 8)
+
+	first := Shuffled(s, rand.New(rand.NewSource(42)))
+	if want := []int{6, 8, 5, 7, 2, 4, 1, 3}; !reflect.DeepEqual(first, want) {
+		t.Errorf("Shuffled: expected %v, got %v", want, first)
+	}
+
+	for i := 0; i < 10; i++ {
+		if again := Shuffled(s, rand.New(rand.NewSource(42))); !reflect.DeepEqual(first, again) {
+			t.Fatalf("Shuffled: same seed should give the same order, got %v and %v", first, again)
+		}
+	}
+}
+
+func Test_sortCanonical(t *testing.T) {
+	type point struct{ X, Y int }
+	items := []any{"b", point{2, 1}, 10, "a", point{1, 2}, 2, nil}
+
+	sortCanonical(items)
+	if want := []any{nil, 2, 10, "a", "b", point{1, 2}, point{2, 1}}; !reflect.DeepEqual(items, want) {
+		t.Error("sortCanonical: expected the items grouped by kind and sorted, got", items)
+	}
+}
+
+func Benchmark_sortCanonical(b *testing.B) {
+	items := rand.New(rand.NewSource(1)).Perm(10000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sortCanonical(slices.Clone(items))
+	}
+}
+
+func Test_SortedList(t *testing.T) {
+	if got := SortedList(newTS(3, 10, 1, 2)); !reflect.DeepEqual(got, []int{1, 2, 3, 10}) {
+		t.Error("SortedList: expected ascending ints, got", got)
+	}
+
+	if got := SortedList(newNonTS("b", "c", "a")); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Error("SortedList: expected ascending strings, got", got)
+	}
+
+	if got := SortedList(newNonTS[int]()); got == nil || len(got) != 0 {
+		t.Error("SortedList: empty set should give an empty non-nil slice, got", got)
+	}
+}
+
+func Test_EqualSortedSlice(t *testing.T) {
+	s := newTS(3, 1, 2)
+
+	if !EqualSortedSlice(s, SortedList(s)) {
+		t.Error("EqualSortedSlice: set should be equal to its sorted list")
+	}
+
+	if EqualSortedSlice(s, []int{1, 2, 3, 4}) {
+		t.Error("EqualSortedSlice: set should not be equal to a slice with an extra item")
+	}
+
+	if !EqualSortedSlice(newNonTS[int](), nil) {
+		t.Error("EqualSortedSlice: empty set should be equal to an empty slice")
+	}
+}
+
+func Test_Sortable(t *testing.T) {
+	s := newTS(3, 1, 4, 5, 9, 2, 6)
+
+	items := Sortable(s)
+	sort.Sort(items)
+	if !reflect.DeepEqual([]int(items), []int{1, 2, 3, 4, 5, 6, 9}) {
+		t.Error("Sortable: expected the items in ascending order, got", items)
+	}
+
+	items[0] = 42
+	if s.Has(42) || !s.Has(1) {
+		t.Error("Sortable: modifying the snapshot should not affect the set, got", s)
+	}
+}
+
+func Test_EachSorted(t *testing.T) {
+	s := newTS("delta", "alpha", "charlie", "bravo", "echo")
+
+	for run := 0; run < 10; run++ {
+		var visited []string
+		if !EachSorted(s, func(item string) bool {
+			visited = append(visited, item)
+			return true
+		}) {
+			t.Fatal("EachSorted: all the items should be visited")
+		}
+		if !reflect.DeepEqual(visited, []string{"alpha", "bravo", "charlie", "delta", "echo"}) {
+			t.Fatal("EachSorted: expected the items in ascending order, got", visited)
+		}
+	}
+
+	var visited []string
+	if EachSorted(s, func(item string) bool {
+		visited = append(visited, item)
+		return item != "bravo"
+	}) || !reflect.DeepEqual(visited, []string{"alpha", "bravo"}) {
+		t.Error("EachSorted: should stop when the callback returns false, visited", visited)
+	}
+}
@@ -0,0 +1,188 @@
+package set
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SQLFormat defines how SQLSet stores its items in a database column.
+type SQLFormat int
+
+const (
+	// PostgresArray stores the items as a Postgres array literal, e.g.
+	// {"a","b"} or {1,2}.
+	PostgresArray SQLFormat = iota
+	// JSONArray stores the items as a JSON array, e.g. ["a","b"] or [1,2].
+	JSONArray
+)
+
+// SQLItem is a type of items SQLSet can store.
+type SQLItem interface {
+	~string | ~int64
+}
+
+// SQLSet wraps a Set to implement driver.Valuer and sql.Scanner, so it can be
+// stored in a text or array column through database/sql. The zero value is
+// ready to be scanned into, it gets a new threadsafe set.
+//
+// Sets are unordered, so the order of the stored items is unspecified and may
+// differ between two stores of the same set.
+type SQLSet[T SQLItem] struct {
+	Set[T]
+	// Format defines how Value renders the items. Scan accepts both formats.
+	Format SQLFormat
+}
+
+// Value renders the items in the configured format. A nil set is stored as
+// NULL.
+func (s SQLSet[T]) Value() (driver.Value, error) {
+	if s.Set == nil {
+		return nil, nil
+	}
+
+	items := s.List()
+	switch s.Format {
+	case PostgresArray:
+		elems := make([]string, len(items))
+		for i, item := range items {
+			elems[i] = formatPostgresElem(item)
+		}
+		return "{" + strings.Join(elems, ",") + "}", nil
+	case JSONArray:
+		b, err := json.Marshal(items)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	default:
+		return nil, fmt.Errorf("set: unknown SQL format %d", s.Format)
+	}
+}
+
+// Scan replaces the items of the set with the items of a Postgres array
+// literal or a JSON array, given as a string or a []byte. NULL gives an empty
+// set.
+func (s *SQLSet[T]) Scan(src any) error {
+	var text string
+	switch src := src.(type) {
+	case nil:
+	case string:
+		text = src
+	case []byte:
+		text = string(src)
+	default:
+		return fmt.Errorf("set: can't scan %T into a set", src)
+	}
+
+	var (
+		items []T
+		err   error
+	)
+	switch text = strings.TrimSpace(text); {
+	case text == "":
+	case strings.HasPrefix(text, "["):
+		err = json.Unmarshal([]byte(text), &items)
+	case strings.HasPrefix(text, "{"):
+		items, err = parsePostgresArray[T](text)
+	default:
+		err = fmt.Errorf("set: can't scan %q into a set: not an array", text)
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.Set == nil {
+		s.Set = New[T]()
+	} else {
+		s.Clear()
+	}
+	s.Add(items...)
+
+	return nil
+}
+
+func formatPostgresElem[T SQLItem](item T) string {
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Int64 {
+		return strconv.FormatInt(v.Int(), 10)
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(v.String()) + `"`
+}
+
+// parsePostgresArray parses a one-dimensional Postgres array literal. NULL
+// elements are skipped.
+func parsePostgresArray[T SQLItem](text string) ([]T, error) {
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return nil, fmt.Errorf("set: malformed array literal %q", text)
+	}
+	body := text[1 : len(text)-1]
+
+	var items []T
+	for body != "" {
+		var (
+			elem   string
+			quoted bool
+		)
+		if body[0] == '"' {
+			var b strings.Builder
+			i := 1
+			for ; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				}
+				b.WriteByte(body[i])
+			}
+			if i == len(body) {
+				return nil, fmt.Errorf("set: unterminated quoted element in %q", text)
+			}
+			elem, quoted, body = b.String(), true, body[i+1:]
+		} else {
+			i := strings.IndexByte(body, ',')
+			if i < 0 {
+				i = len(body)
+			}
+			elem, body = strings.TrimSpace(body[:i]), body[i:]
+		}
+
+		if body != "" {
+			if body[0] != ',' {
+				return nil, fmt.Errorf("set: malformed array literal %q", text)
+			}
+			body = body[1:]
+		}
+
+		if !quoted && strings.EqualFold(elem, "NULL") {
+			continue
+		}
+
+		item, err := parseSQLItem[T](elem)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func parseSQLItem[T SQLItem](elem string) (T, error) {
+	var item T
+	v := reflect.ValueOf(&item).Elem()
+	if v.Kind() == reflect.Int64 {
+		n, err := strconv.ParseInt(elem, 10, 64)
+		if err != nil {
+			return item, fmt.Errorf("set: parsing array element: %w", err)
+		}
+		v.SetInt(n)
+	} else {
+		v.SetString(elem)
+	}
+
+	return item, nil
+}
@@ -0,0 +1,67 @@
+package set
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = SQLSet[string]{}
+	_ sql.Scanner   = (*SQLSet[string])(nil)
+)
+
+func TestSQLSet_roundTrip(t *testing.T) {
+	for _, format := range []SQLFormat{PostgresArray, JSONArray} {
+		s := SQLSet[string]{Set: New("a", `b"c`, `d\e`, "f,g", "NULL"), Format: format}
+		testSQL(t, s, &SQLSet[string]{})
+
+		n := SQLSet[int64]{Set: NewNonTS[int64](1, -2, 3), Format: format}
+		testSQL(t, n, &SQLSet[int64]{})
+	}
+}
+
+func testSQL[T SQLItem](t *testing.T, s SQLSet[T], scanned *SQLSet[T]) {
+	t.Helper()
+
+	value, err := s.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// drivers hand text columns over as []byte
+	if err := scanned.Scan([]byte(value.(string))); err != nil {
+		t.Fatal(err)
+	}
+	if !scanned.IsEqual(s.Set) {
+		t.Errorf("Scan: expected %v, got %v from %s", s, scanned, value)
+	}
+}
+
+func TestSQLSet_Scan(t *testing.T) {
+	s := SQLSet[int64]{Set: New[int64](9)}
+	if err := s.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsEmpty() {
+		t.Error("Scan: NULL should give an empty set, got", s)
+	}
+
+	if err := s.Scan(`{1, 2,NULL,2}`); err != nil {
+		t.Fatal(err)
+	}
+	if s.Size() != 2 || !s.Has(1, 2) {
+		t.Error("Scan: expected the items one and two, got", s)
+	}
+
+	if err := s.Scan(`{1,x}`); err == nil {
+		t.Error("Scan: malformed number should fail")
+	}
+	if err := s.Scan(42); err == nil {
+		t.Error("Scan: unsupported source type should fail")
+	}
+
+	if value, _ := (SQLSet[string]{}).Value(); value != nil {
+		t.Error("Value: nil set should be stored as NULL, got", value)
+	}
+}
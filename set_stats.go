@@ -0,0 +1,41 @@
+package set
+
+import "math"
+
+// Entropy returns the Shannon entropy, in bits, of the distribution produced
+// by weighting every element of s with the given function. The set is treated
+// as a categorical distribution: each weight is normalized by the sum of all
+// weights. Elements with a zero (or negative) weight are skipped, so a set
+// where all weights are equal has an entropy of log2(n).
+func Entropy[T comparable](s Set[T], weight func(T) float64) float64 {
+	weights := make([]float64, 0, s.Size())
+	total := 0.0
+	s.Each(func(item T) bool {
+		if w := weight(item); w > 0 {
+			weights = append(weights, w)
+			total += w
+		}
+		return true
+	})
+
+	entropy := 0.0
+	for _, w := range weights {
+		p := w / total
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// Histogram returns the number of items of s falling into each bucket, as
+// computed by the bucket function. Unlike grouping the items into subsets, it
+// allocates nothing but the resulting map.
+func Histogram[T comparable, K comparable](s Set[T], bucket func(T) K) map[K]int {
+	counts := make(map[K]int)
+	s.Each(func(item T) bool {
+		counts[bucket(item)]++
+		return true
+	})
+
+	return counts
+}
@@ -0,0 +1,36 @@
+package set
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_Entropy(t *testing.T) {
+	s := newNonTS[string]()
+	s.Add("a", "b", "c", "d")
+
+	e := Entropy(s, func(string) float64 { return 1 })
+	if math.Abs(e-2.0) > 1e-9 {
+		t.Error("Entropy: uniform set of four items should have 2 bits of entropy, got", e)
+	}
+
+	e = Entropy(s, func(item string) float64 {
+		if item == "a" {
+			return 1
+		}
+		return 0
+	})
+	if e != 0 {
+		t.Error("Entropy: zero-weight items should be skipped, got", e)
+	}
+}
+
+func Test_Histogram(t *testing.T) {
+	s := newTS(0, 1, 2, 3, 4, 5, 6)
+
+	h := Histogram(s, func(item int) int { return item % 3 })
+	if !reflect.DeepEqual(h, map[int]int{0: 3, 1: 2, 2: 2}) {
+		t.Error("Histogram: unexpected counts of the buckets", h)
+	}
+}
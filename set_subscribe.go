@@ -0,0 +1,154 @@
+package set
+
+import "sync"
+
+// SetDelta describes a single change of a set: items which were added to it
+// and items which were removed from it.
+type SetDelta[T any] struct {
+	Added   []T
+	Removed []T
+}
+
+// Subscriber is implemented by sets which are able to stream their changes.
+// The initial snapshot returned by Subscribe plus every delta received from the
+// updates channel allows a consumer to maintain an exact mirror of the set.
+type Subscriber[T any] interface {
+	Subscribe() (initial []T, updates <-chan SetDelta[T])
+	// Unsubscribe stops the delivery of deltas and closes the updates channel.
+	// Deltas which weren't received yet are dropped.
+	Unsubscribe(updates <-chan SetDelta[T])
+}
+
+var _ Subscriber[int] = (*setm[int])(nil)
+
+// subscription queues the deltas of a set for a single subscriber, so a slow
+// consumer never blocks the mutations of the set itself.
+type subscription[T any] struct {
+	mu    sync.Mutex
+	queue []SetDelta[T]
+	wake  chan null
+	done  chan null
+	out   chan SetDelta[T]
+}
+
+func newSubscription[T any]() *subscription[T] {
+	sub := &subscription[T]{
+		wake: make(chan null, 1),
+		done: make(chan null),
+		out:  make(chan SetDelta[T]),
+	}
+	go sub.run()
+
+	return sub
+}
+
+func (sub *subscription[T]) push(delta SetDelta[T]) {
+	sub.mu.Lock()
+	sub.queue = append(sub.queue, delta)
+	sub.mu.Unlock()
+
+	select {
+	case sub.wake <- null{}:
+	default: // already woken up
+	}
+}
+
+func (sub *subscription[T]) run() {
+	defer close(sub.out)
+
+	for {
+		sub.mu.Lock()
+		if len(sub.queue) == 0 {
+			sub.mu.Unlock()
+			select {
+			case <-sub.wake:
+				continue
+			case <-sub.done:
+				return
+			}
+		}
+		delta := sub.queue[0]
+		sub.queue = sub.queue[1:]
+		sub.mu.Unlock()
+
+		select {
+		case sub.out <- delta:
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// Subscribe returns a snapshot of the items in the set and a channel which
+// receives every following change of the set, in the order they were made.
+func (s *setm[T]) Subscribe() (initial []T, updates <-chan SetDelta[T]) {
+	s.Lock()
+	defer s.Unlock()
+
+	sub := newSubscription[T]()
+	s.subs = append(s.subs, sub)
+
+	return s.set.List(), sub.out
+}
+
+// Unsubscribe stops the delivery of deltas and closes the updates channel.
+func (s *setm[T]) Unsubscribe(updates <-chan SetDelta[T]) {
+	s.Lock()
+	defer s.Unlock()
+
+	for i, sub := range s.subs {
+		if (<-chan SetDelta[T])(sub.out) == updates {
+			s.subs = append(s.subs[:i], s.subs[i+1:]...)
+			close(sub.done)
+			return
+		}
+	}
+}
+
+// publish notifies the subscribers about a change. It must be called with the
+// write lock held.
+func (s *setm[T]) publish(added, removed []T) {
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	for _, sub := range s.subs {
+		sub.push(SetDelta[T]{Added: added, Removed: removed})
+	}
+}
+
+// ChangeEvent describes a change of a single item of a set.
+type ChangeEvent[T any] struct {
+	Item  T
+	Added bool // false if the item was removed
+}
+
+// ChangeNotifier is implemented by sets which are able to call back on their
+// changes.
+type ChangeNotifier[T any] interface {
+	// OnChange registers a callback, which receives an event for every item
+	// added to or removed from the set, in the order the changes were made.
+	// The returned function stops the delivery of events.
+	OnChange(f func(event ChangeEvent[T])) (stop func())
+}
+
+var _ ChangeNotifier[int] = (*setm[int])(nil)
+
+// OnChange calls f for every item added to or removed from the set. The
+// callbacks are run one at a time on a separate goroutine, so they may access
+// the set, while a slow callback never blocks the mutations of the set.
+func (s *setm[T]) OnChange(f func(event ChangeEvent[T])) (stop func()) {
+	_, updates := s.Subscribe()
+	go func() {
+		for delta := range updates {
+			for _, item := range delta.Added {
+				f(ChangeEvent[T]{Item: item, Added: true})
+			}
+			for _, item := range delta.Removed {
+				f(ChangeEvent[T]{Item: item})
+			}
+		}
+	}()
+
+	return func() { s.Unsubscribe(updates) }
+}
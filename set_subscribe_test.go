@@ -0,0 +1,86 @@
+package set
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSet_Subscribe(t *testing.T) {
+	s := newTS[string]("initial")
+	sub := s.(Subscriber[string])
+
+	initial, updates := sub.Subscribe()
+	mirror := newNonTS[string](initial...)
+
+	s.Pop()
+	s.Add("a", "b")
+	s.Add("a") // nothing changes, no delta is sent
+	s.Remove("a", "missing")
+	s.Merge(newNonTS[string]("c", "d"))
+	s.Separate(newNonTS[string]("c"))
+	s.Add("e")
+
+	for i := 0; i < 6; i++ {
+		select {
+		case delta := <-updates:
+			mirror.Add(delta.Added...)
+			mirror.Remove(delta.Removed...)
+		case <-time.After(time.Second):
+			t.Fatal("Subscribe: expected a delta, got nothing")
+		}
+	}
+
+	if !mirror.IsEqual(s) {
+		t.Errorf("Subscribe: mirror %v doesn't match the set %v", mirror, s)
+	}
+
+	sub.Unsubscribe(updates)
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Error("Subscribe: no more deltas were expected")
+		}
+	case <-time.After(time.Second):
+		t.Error("Unsubscribe: updates channel should be closed")
+	}
+}
+
+func TestSet_OnChange(t *testing.T) {
+	s := newTS[string]("initial")
+
+	const subscribers = 2
+	events := make([]chan ChangeEvent[string], subscribers)
+	for i := range events {
+		events[i] = make(chan ChangeEvent[string], 16)
+		ch := events[i]
+		stop := s.(ChangeNotifier[string]).OnChange(func(event ChangeEvent[string]) {
+			ch <- event
+		})
+		defer stop()
+	}
+
+	s.Add("a")
+	s.Add("a") // nothing changes, no event is sent
+	s.Remove("initial", "missing")
+	s.Add("b")
+	s.Remove("a")
+
+	expected := []ChangeEvent[string]{
+		{Item: "a", Added: true},
+		{Item: "initial"},
+		{Item: "b", Added: true},
+		{Item: "a"},
+	}
+	for i, ch := range events {
+		for _, want := range expected {
+			select {
+			case got := <-ch:
+				if got != want {
+					t.Errorf("OnChange: subscriber %d expected %v, got %v", i, want, got)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("OnChange: subscriber %d expected %v, got nothing", i, want)
+			}
+		}
+	}
+}
@@ -2,19 +2,22 @@ package set
 
 import (
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 )
 
 func Test_Union(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3")
-	r := newTS()
+	r := newTS[string]()
 	r.Add("3", "4", "5")
-	x := newNonTS()
+	x := newNonTS[string]()
 	x.Add("5", "6", "7")
 
 	u := Union(s, r, x)
-	if settype := reflect.TypeOf(u).String(); settype != "*set.Set" {
+	if settype := reflect.TypeOf(u).String(); settype != "*set.setm[string]" {
 		t.Error("Union should derive its set type from the first passed set, got", settype)
 	}
 	if u.Size() != 7 {
@@ -29,18 +32,18 @@ func Test_Union(t *testing.T) {
 	if z.Size() != 5 {
 		t.Error("Union: Union of 2 sets doesn't have the proper number of items.")
 	}
-	if settype := reflect.TypeOf(z).String(); settype != "*set.SetNonTS" {
+	if settype := reflect.TypeOf(z).String(); settype != "*set.set[string]" {
 		t.Error("Union should derive its set type from the first passed set, got", settype)
 	}
 
 }
 
 func Test_Difference(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3")
-	r := newTS()
+	r := newTS[string]()
 	r.Add("3", "4", "5")
-	x := newNonTS()
+	x := newNonTS[string]()
 	x.Add("5", "6", "7")
 
 	u := Difference(s, r, x)
@@ -61,11 +64,11 @@ func Test_Difference(t *testing.T) {
 }
 
 func Test_Intersection(t *testing.T) {
-	s1 := newTS()
+	s1 := newTS[string]()
 	s1.Add("1", "3", "4", "5")
-	s2 := newTS()
+	s2 := newTS[string]()
 	s2.Add("3", "5", "6")
-	s3 := newTS()
+	s3 := newTS[string]()
 	s3.Add("4", "5", "6", "7")
 	u := Intersection(s1, s2, s3)
 
@@ -79,9 +82,9 @@ func Test_Intersection(t *testing.T) {
 }
 
 func Test_Intersection2(t *testing.T) {
-	s1 := newTS()
+	s1 := newTS[string]()
 	s1.Add("1", "3", "4", "5")
-	s2 := newTS()
+	s2 := newTS[string]()
 	s2.Add("5", "6")
 	i := Intersection(s1, s2)
 
@@ -94,10 +97,53 @@ func Test_Intersection2(t *testing.T) {
 	}
 }
 
+func Test_Without(t *testing.T) {
+	s := newTS(1, 2, 3)
+	u := Without(s, newNonTS(2, 4))
+
+	if !u.IsEqual(newNonTS(1, 3)) {
+		t.Error("Without: expected the items missing in the other set, got", u)
+	}
+	if s.Size() != 3 {
+		t.Error("Without: source set should not be modified, got", s)
+	}
+
+	s.Separate(newNonTS(2, 4))
+	if !s.IsEqual(newNonTS(1, 3)) {
+		t.Error("Separate: set should shrink, got", s)
+	}
+}
+
+func Test_Diff(t *testing.T) {
+	old := newTS("a", "b", "c")
+	new := newNonTS("b", "c", "d", "e")
+
+	added, removed := Diff(old, new)
+	if !added.IsEqual(newNonTS("d", "e")) {
+		t.Error("Diff: expected the items of the new set only to be added, got", added)
+	}
+	if !removed.IsEqual(newNonTS("a")) {
+		t.Error("Diff: expected the items of the old set only to be removed, got", removed)
+	}
+	if !IsDisjoint(added, removed) {
+		t.Error("Diff: added and removed items should be disjoint")
+	}
+
+	old.Merge(added).Separate(removed)
+	if !old.IsEqual(new) {
+		t.Error("Diff: applying the diff to the old set should yield the new one, got", old)
+	}
+
+	added, removed = Diff(old, new)
+	if !added.IsEmpty() || !removed.IsEmpty() {
+		t.Error("Diff: equal sets should have no changes, got", added, removed)
+	}
+}
+
 func Test_SymmetricDifference(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3")
-	r := newTS()
+	r := newTS[string]()
 	r.Add("3", "4", "5")
 	u := SymmetricDifference(s, r)
 
@@ -110,9 +156,26 @@ func Test_SymmetricDifference(t *testing.T) {
 	}
 }
 
+func Test_SymmetricDifferenceCount(t *testing.T) {
+	a := newTS(1, 2, 3)
+	b := newNonTS(2, 3, 4)
+
+	if n := SymmetricDifferenceCount(a, b); n != 2 {
+		t.Error("SymmetricDifferenceCount: expected two, got", n)
+	}
+
+	if n := SymmetricDifferenceCount(a, b); n != SymmetricDifference(a, b).Size() {
+		t.Error("SymmetricDifferenceCount: should match the size of SymmetricDifference, got", n)
+	}
+
+	if n := SymmetricDifferenceCount(a, a); n != 0 {
+		t.Error("SymmetricDifferenceCount: a set should not differ from itself, got", n)
+	}
+}
+
 func Test_StringSlice(t *testing.T) {
-	s := newTS()
-	s.Add("san francisco", "istanbul", 3.14, 1321, "ankara")
+	s := newTS[string]()
+	s.Add("san francisco", "istanbul", "ankara")
 	u := StringSlice(s)
 
 	if len(u) != 3 {
@@ -120,16 +183,15 @@ func Test_StringSlice(t *testing.T) {
 	}
 
 	for _, item := range u {
-		r := reflect.TypeOf(item)
-		if r.Kind().String() != "string" {
-			t.Error("StringSlice: slice item should be a string")
+		if !s.Has(item) {
+			t.Error("StringSlice: slice item should be in the set, got", item)
 		}
 	}
 }
 
 func Test_IntSlice(t *testing.T) {
-	s := newTS()
-	s.Add("san francisco", "istanbul", 3.14, 1321, "ankara", 8876)
+	s := newTS[int]()
+	s.Add(1321, 8876)
 	u := IntSlice(s)
 
 	if len(u) != 2 {
@@ -137,16 +199,15 @@ func Test_IntSlice(t *testing.T) {
 	}
 
 	for _, item := range u {
-		r := reflect.TypeOf(item)
-		if r.Kind().String() != "int" {
-			t.Error("Intslice: slice item should be a int")
+		if !s.Has(item) {
+			t.Error("IntSlice: slice item should be in the set, got", item)
 		}
 	}
 }
 
 func BenchmarkSetEquality(b *testing.B) {
-	s := newTS()
-	u := newTS()
+	s := newTS[int]()
+	u := newTS[int]()
 
 	for i := 0; i < b.N; i++ {
 		s.Add(i)
@@ -161,8 +222,8 @@ func BenchmarkSetEquality(b *testing.B) {
 }
 
 func BenchmarkSubset(b *testing.B) {
-	s := newTS()
-	u := newTS()
+	s := newTS[int]()
+	u := newTS[int]()
 
 	for i := 0; i < b.N; i++ {
 		s.Add(i)
@@ -177,8 +238,8 @@ func BenchmarkSubset(b *testing.B) {
 }
 
 func benchmarkIntersection(b *testing.B, numberOfItems int) {
-	s1 := newTS()
-	s2 := newTS()
+	s1 := newTS[int]()
+	s2 := newTS[int]()
 
 	for i := 0; i < numberOfItems/2; i++ {
 		s1.Add(i)
@@ -187,12 +248,92 @@ func benchmarkIntersection(b *testing.B, numberOfItems int) {
 		s2.Add(i)
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		Intersection(s1, s2)
 	}
 }
 
+// intersectionUnion is the former Intersection, which built the union of all
+// the sets and removed the items missing in any of them.
+func intersectionUnion[T comparable](set1, set2 Set[T], sets ...Set[T]) Set[T] {
+	all := Union(set1, set2, sets...)
+	result := Union(set1, set2, sets...)
+
+	all.Each(func(item T) bool {
+		if !set1.Has(item) || !set2.Has(item) {
+			result.Remove(item)
+		}
+
+		for _, set := range sets {
+			if !set.Has(item) {
+				result.Remove(item)
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// unionPerItem is Union adding the items one by one, kept for comparison.
+func unionPerItem[T comparable](set1, set2 Set[T], sets ...Set[T]) Set[T] {
+	u := set1.Copy()
+	for _, set := range append([]Set[T]{set2}, sets...) {
+		set.Each(func(item T) bool {
+			u.Add(item)
+			return true
+		})
+	}
+
+	return u
+}
+
+func benchmarkUnionLarge(b *testing.B, union func(set1, set2 Set[int], sets ...Set[int]) Set[int]) {
+	const items = 1000000
+
+	set1, set2 := newTS[int](), newTS[int]()
+	for i := 0; i < items; i++ {
+		set1.Add(i)
+		set2.Add(i + items/2)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		union(set1, set2)
+	}
+}
+
+func BenchmarkUnionLarge(b *testing.B) {
+	benchmarkUnionLarge(b, Union[int])
+}
+
+func BenchmarkUnionLargePerItem(b *testing.B) {
+	benchmarkUnionLarge(b, unionPerItem[int])
+}
+
+func benchmarkIntersectionSmall(b *testing.B, intersection func(set1, set2 Set[int], sets ...Set[int]) Set[int]) {
+	large := newTS[int]()
+	for i := 0; i < 1000000; i++ {
+		large.Add(i)
+	}
+	small := newTS(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		intersection(large, small)
+	}
+}
+
+func BenchmarkIntersectionSmall(b *testing.B) {
+	benchmarkIntersectionSmall(b, Intersection[int])
+}
+
+func BenchmarkIntersectionSmallUnion(b *testing.B) {
+	benchmarkIntersectionSmall(b, intersectionUnion[int])
+}
+
 func BenchmarkIntersection10(b *testing.B) {
 	benchmarkIntersection(b, 10)
 }
@@ -216,3 +357,930 @@ func BenchmarkIntersection100000(b *testing.B) {
 func BenchmarkIntersection1000000(b *testing.B) {
 	benchmarkIntersection(b, 1000000)
 }
+
+func Test_Equal(t *testing.T) {
+	if !Equal[hashInt](nil, nil) {
+		t.Error("Equal: two nil sets should be equal")
+	}
+	if !Equal(nil, NewNonTS[hashInt]()) || !Equal(New[hashInt](), nil) {
+		t.Error("Equal: nil set should be equal to an empty set")
+	}
+	if Equal(nil, New[hashInt](1)) || Equal(NewAny[hashInt](1), nil) {
+		t.Error("Equal: nil set should not be equal to a non-empty set")
+	}
+
+	sets := map[string]Set[hashInt]{
+		"New":         New[hashInt](1, 2, 3),
+		"NewNonTS":    NewNonTS[hashInt](1, 2, 3),
+		"NewAny":      NewAny[hashInt](1, 2, 3),
+		"NewAnyNonTS": NewAnyNonTS[hashInt](1, 2, 3),
+	}
+	for nameA, a := range sets {
+		for nameB, b := range sets {
+			if !Equal(a, b) {
+				t.Errorf("Equal: %s and %s should be equal", nameA, nameB)
+			}
+		}
+		if Equal(a, New[hashInt](1, 2, 4)) || Equal(NewNonTS[hashInt](1, 2), a) {
+			t.Errorf("Equal: %s should not be equal to different sets", nameA)
+		}
+	}
+}
+
+// collidingInt hashes to only two buckets, so the sets created by NewAny
+// tell its values apart by the == operator.
+type collidingInt int
+
+func (c collidingInt) Hash() (uint64, error) { return uint64(c % 2), nil }
+
+func Test_IsEqual_implementations(t *testing.T) {
+	constructors := map[string]func(...collidingInt) Set[collidingInt]{
+		"New":         New[collidingInt],
+		"NewNonTS":    NewNonTS[collidingInt],
+		"NewAny":      NewAny[collidingInt],
+		"NewAnyNonTS": NewAnyNonTS[collidingInt],
+	}
+	for nameA, newA := range constructors {
+		for nameB, newB := range constructors {
+			a := newA(1, 2, 3, 4, 5)
+			if !a.IsEqual(newB(5, 4, 3, 2, 1)) || !Equal(a, newB(5, 4, 3, 2, 1)) {
+				t.Errorf("IsEqual: %s and %s with the same items should be equal", nameA, nameB)
+			}
+			if a.IsEqual(newB(1, 2, 3, 4, 7)) || Equal(a, newB(1, 2, 3, 4, 7)) {
+				t.Errorf("IsEqual: %s and %s with colliding items should not be equal", nameA, nameB)
+			}
+			if a.IsEqual(newB(1, 2, 3, 4)) || Equal(newB(1, 2, 3, 4), a) {
+				t.Errorf("IsEqual: %s and %s of different sizes should not be equal", nameA, nameB)
+			}
+		}
+	}
+}
+
+func Test_EqualExcept(t *testing.T) {
+	a := newNonTS[string]("1", "2", "3")
+	b := newTS[string]("1", "2", "4")
+	ignore := newNonTS[string]("3", "4")
+
+	if !EqualExcept(a, b, ignore) {
+		t.Error("EqualExcept: sets differing only by ignored items should be equal")
+	}
+
+	if EqualExcept(a, b, newNonTS[string]("3")) {
+		t.Error("EqualExcept: sets differing by a not ignored item should not be equal")
+	}
+}
+
+func Test_AlgebraComparable(t *testing.T) {
+	type point struct{ x, y int }
+
+	a := newTS(point{0, 0}, point{1, 1}, point{2, 2})
+	b := newNonTS(point{1, 1}, point{2, 2}, point{3, 3})
+
+	if u := Union(a, b); u.Size() != 4 {
+		t.Error("Union: expected four points, got", u)
+	}
+
+	if d := Difference(a, b); d.Size() != 1 || !d.Has(point{0, 0}) {
+		t.Error("Difference: expected only the origin, got", d)
+	}
+
+	if i := Intersection(a, b); i.Size() != 2 || !i.Has(point{1, 1}, point{2, 2}) {
+		t.Error("Intersection: expected two shared points, got", i)
+	}
+
+	if s := SymmetricDifference(a, b); s.Size() != 2 || !s.Has(point{0, 0}, point{3, 3}) {
+		t.Error("SymmetricDifference: expected two distinct points, got", s)
+	}
+}
+
+func Test_CopyInto(t *testing.T) {
+	for name, dst := range map[string]Set[int]{
+		"New":             New(7, 8, 9),
+		"NewNonTS":        NewNonTS(7, 8, 9),
+		"NewFair":         NewFair(7, 8, 9),
+		"NewNonTSChecked": NewNonTSChecked(7, 8, 9),
+	} {
+		for _, src := range []Set[int]{New(1, 2, 3), NewNonTS(1, 2, 3)} {
+			CopyInto(src, dst)
+			if !dst.IsEqual(src) {
+				t.Errorf("%s: CopyInto should replace the items with the source, got %v", name, dst)
+			}
+
+			dst.Add(4)
+			if src.Has(4) {
+				t.Errorf("%s: CopyInto should not share the items with the source", name)
+			}
+		}
+
+		CopyInto(dst, dst)
+		if dst.Size() != 4 {
+			t.Errorf("%s: CopyInto into itself should keep the items, got %v", name, dst)
+		}
+	}
+}
+
+func BenchmarkCopyInto(b *testing.B) {
+	src := newTS[int]()
+	for i := 0; i < 1000; i++ {
+		src.Add(i)
+	}
+	dst := newTS[int]()
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		CopyInto(src, dst)
+	}
+}
+
+func BenchmarkCopy(b *testing.B) {
+	src := newTS[int]()
+	for i := 0; i < 1000; i++ {
+		src.Add(i)
+	}
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		src.Copy()
+	}
+}
+
+func Test_AllOfType(t *testing.T) {
+	s := newNonTS[any](1, 2, 3)
+
+	ints, ok := AllOfType[int](s)
+	if !ok {
+		t.Fatal("AllOfType: set of ints should be narrowed to Set[int]")
+	}
+	if ints.Size() != 3 || !ints.Has(1, 2, 3) {
+		t.Error("AllOfType: narrowed set should have all the items, got", ints)
+	}
+
+	s.Add("fatih")
+	if _, ok := AllOfType[int](s); ok {
+		t.Error("AllOfType: set of mixed types should not be narrowed to Set[int]")
+	}
+}
+
+func Test_NewItems(t *testing.T) {
+	for name, newSet := range map[string]func(...string) Set[string]{
+		"New":      New[string],
+		"NewNonTS": NewNonTS[string],
+	} {
+		if s := newSet(); s.Size() != 0 {
+			t.Errorf("%s: calling without items should create an empty set, got %v", name, s)
+		}
+
+		if s := newSet("a"); s.Size() != 1 || !s.Has("a") {
+			t.Errorf("%s: calling with a single item should add it, got %v", name, s)
+		}
+
+		if s := newSet("a", "b", "a"); s.Size() != 2 || !s.Has("a", "b") {
+			t.Errorf("%s: duplicate items should be added once, got %v", name, s)
+		}
+	}
+}
+
+func Test_All(t *testing.T) {
+	for name, s := range map[string]Set[int]{
+		"New":      New(1, 2, 3, 4),
+		"NewNonTS": NewNonTS(1, 2, 3, 4),
+		"NewFair":  NewFair(1, 2, 3, 4),
+	} {
+		testAll(t, name, s)
+	}
+
+	testAll(t, "NewAny", NewAny[hashInt](1, 2, 3, 4))
+	testAll(t, "NewAnyNonTS", NewAnyNonTS[hashInt](1, 2, 3, 4))
+}
+
+func testAll[T comparable](t *testing.T, name string, s Set[T]) {
+	t.Helper()
+
+	visited := make(map[T]int)
+	for item := range s.All() {
+		visited[item]++
+	}
+	if len(visited) != s.Size() {
+		t.Errorf("%s: All should yield every item, got %v", name, visited)
+	}
+	for item, n := range visited {
+		if n != 1 || !s.Has(item) {
+			t.Errorf("%s: All should yield %v exactly once, got %d", name, item, n)
+		}
+	}
+
+	n := 0
+	for range s.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("%s: All should stop after break, visited %d items", name, n)
+	}
+
+	// the read lock of the threadsafe sets is released after break
+	s.Add(s.List()...)
+}
+
+func Test_Filter(t *testing.T) {
+	even := func(item int) bool { return item%2 == 0 }
+
+	for name, s := range map[string]Set[int]{
+		"New":      New(1, 2, 3, 4, 5, 6),
+		"NewNonTS": NewNonTS(1, 2, 3, 4, 5, 6),
+		"NewFair":  NewFair(1, 2, 3, 4, 5, 6),
+	} {
+		f := s.Filter(even)
+		if f.Size() != 3 || !f.Has(2, 4, 6) {
+			t.Errorf("%s: Filter should keep only even items, got %v", name, f)
+		}
+		if s.Size() != 6 {
+			t.Errorf("%s: Filter should not modify the original set, got %v", name, s)
+		}
+		if reflect.TypeOf(f) != reflect.TypeOf(s) {
+			t.Errorf("%s: Filter should return a set of the same kind, got %T", name, f)
+		}
+	}
+
+	for name, s := range map[string]Set[hashInt]{
+		"NewAny":      NewAny[hashInt](1, 2, 3, 4),
+		"NewAnyNonTS": NewAnyNonTS[hashInt](1, 2, 3, 4),
+	} {
+		f := s.Filter(func(item hashInt) bool { return item%2 == 0 })
+		if f.Size() != 2 || !f.Has(2, 4) || s.Size() != 4 {
+			t.Errorf("%s: Filter should keep only even items, got %v", name, f)
+		}
+		if reflect.TypeOf(f) != reflect.TypeOf(s) {
+			t.Errorf("%s: Filter should return a set of the same kind, got %T", name, f)
+		}
+	}
+}
+
+func Test_RemoveIf(t *testing.T) {
+	even := func(item int) bool { return item%2 == 0 }
+
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":             New[int],
+		"NewNonTS":        NewNonTS[int],
+		"NewFair":         func(items ...int) Set[int] { return NewFair(items...) },
+		"NewNonTSChecked": NewNonTSChecked[int],
+	} {
+		s := newSet(1, 2, 3, 4, 5, 6)
+		s.RemoveIf(even)
+		if s.Size() != 3 || !s.Has(1, 3, 5) {
+			t.Errorf("%s: RemoveIf should delete the even items, got %v", name, s)
+		}
+
+		s.RemoveIf(func(int) bool { return false })
+		if s.Size() != 3 {
+			t.Errorf("%s: RemoveIf should delete nothing, got %v", name, s)
+		}
+
+		s = newSet(1, 2, 3, 4, 5, 6).Retain(even)
+		if s.Size() != 3 || !s.Has(2, 4, 6) {
+			t.Errorf("%s: Retain should keep the even items, got %v", name, s)
+		}
+
+		s.RemoveIf(func(int) bool { return true })
+		if !s.IsEmpty() {
+			t.Errorf("%s: RemoveIf should delete every item, got %v", name, s)
+		}
+	}
+
+	s := newTS(1, 2, 3).RemoveIf(even).(*setm[int])
+	if len(s.m) != 2 {
+		t.Error("RemoveIf: backing map should shrink, got", len(s.m))
+	}
+
+	for name, s := range map[string]Set[hashInt]{
+		"NewAny":      NewAny[hashInt](1, 2, 3, 4),
+		"NewAnyNonTS": NewAnyNonTS[hashInt](1, 2, 3, 4),
+	} {
+		s.RemoveIf(func(item hashInt) bool { return item%2 == 0 })
+		if s.Size() != 2 || !s.Has(1, 3) || s.Has(2) {
+			t.Errorf("%s: RemoveIf should delete the even items, got %v", name, s)
+		}
+	}
+}
+
+func Test_Intersection_smallest(t *testing.T) {
+	large := newTS[int]()
+	for i := 0; i < 1000; i++ {
+		large.Add(i)
+	}
+	small := newNonTS(5, 500, 5000)
+
+	i := Intersection(large, small)
+	if i.Size() != 2 || !i.Has(5, 500) {
+		t.Error("Intersection: expected the two shared items, got", i)
+	}
+	if _, ok := i.(*setm[int]); !ok {
+		t.Errorf("Intersection: result should be of the first set's kind, got %T", i)
+	}
+
+	if !intersectionUnion(large, small).IsEqual(i) {
+		t.Error("Intersection: result should match the union-based approach")
+	}
+}
+
+func Test_Intersection_overlapping(t *testing.T) {
+	// the result must match the one of the former, union based implementation
+	sets := []Set[int]{
+		newTS(1, 2, 3, 4, 5, 6),
+		newNonTS(2, 3, 4, 5, 6, 7),
+		newTS(0, 3, 4, 5, 6, 8),
+		newNonTS(4, 5, 6, 9),
+	}
+
+	for n := 2; n <= len(sets); n++ {
+		got := Intersection(sets[0], sets[1], sets[2:n]...)
+		want := intersectionUnion(sets[0], sets[1], sets[2:n]...)
+		if !got.IsEqual(want) {
+			t.Errorf("Intersection: of %d sets expected %v, got %v", n, want, got)
+		}
+	}
+}
+
+func Test_IntersectionN(t *testing.T) {
+	if i := IntersectionN[int](); i == nil || !i.IsEmpty() {
+		t.Error("IntersectionN: no sets should give an empty set, got", i)
+	}
+
+	s := newNonTS(1, 2, 3)
+	i := IntersectionN(s)
+	if !i.IsEqual(s) {
+		t.Error("IntersectionN: single set should give its copy, got", i)
+	}
+	i.Add(4)
+	if s.Has(4) {
+		t.Error("IntersectionN: single set should be copied, not returned")
+	}
+
+	if i := IntersectionN(s, newTS(2, 3, 4)); i.Size() != 2 || !i.Has(2, 3) {
+		t.Error("IntersectionN: expected the two shared items, got", i)
+	}
+
+	i = IntersectionN(
+		newTS(1, 2, 3, 4, 5),
+		newNonTS(2, 3, 4, 5),
+		newTS(3, 4, 5, 6),
+		newNonTS(0, 3, 5),
+		newTS(5, 3),
+	)
+	if i.Size() != 2 || !i.Has(3, 5) {
+		t.Error("IntersectionN: expected the two items shared by all five sets, got", i)
+	}
+	if _, ok := i.(*setm[int]); !ok {
+		t.Errorf("IntersectionN: result should be of the first set's kind, got %T", i)
+	}
+}
+
+func Test_IsProperSubset(t *testing.T) {
+	s := newTS(1, 2, 3)
+
+	if !IsProperSubset(s, newNonTS(1, 2)) || !IsProperSuperset(newNonTS(1, 2), s) {
+		t.Error("IsProperSubset: smaller set of shared items should be a proper subset")
+	}
+	if IsProperSubset(s, newTS(1, 2, 3)) || IsProperSuperset(s, newTS(1, 2, 3)) || IsProperSubset(s, s) {
+		t.Error("IsProperSubset: equal sets should not be proper subsets or supersets")
+	}
+	if !s.IsSubset(newTS(1, 2, 3)) {
+		t.Error("IsSubset: equal sets should be subsets")
+	}
+	if IsProperSubset(s, newNonTS(1, 4)) || IsProperSuperset(newNonTS(1, 4), s) {
+		t.Error("IsProperSubset: smaller set with other items should not be a proper subset")
+	}
+	if IsProperSubset(newNonTS(1, 2), s) {
+		t.Error("IsProperSubset: bigger set should not be a proper subset")
+	}
+}
+
+func Test_IsDisjoint(t *testing.T) {
+	a := newTS(1, 2, 3)
+
+	if !IsDisjoint(a, newNonTS(4, 5)) {
+		t.Error("IsDisjoint: sets without shared items should be disjoint")
+	}
+
+	if IsDisjoint(a, newTS(3, 4, 5, 6)) {
+		t.Error("IsDisjoint: partially overlapping sets should not be disjoint")
+	}
+
+	if IsDisjoint(a, a) {
+		t.Error("IsDisjoint: identical sets should not be disjoint")
+	}
+
+	if !IsDisjoint(a, newNonTS[int]()) {
+		t.Error("IsDisjoint: any set should be disjoint with an empty one")
+	}
+}
+
+func Test_PopN(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":             New[int],
+		"NewNonTS":        NewNonTS[int],
+		"NewFair":         func(items ...int) Set[int] { return NewFair(items...) },
+		"NewNonTSChecked": NewNonTSChecked[int],
+	} {
+		s := newSet(1, 2, 3, 4, 5)
+
+		if popped := s.PopN(0); len(popped) != 0 || s.Size() != 5 {
+			t.Errorf("%s: PopN(0) should pop nothing, got %v", name, popped)
+		}
+
+		popped := s.PopN(10)
+		if len(popped) != 5 {
+			t.Errorf("%s: PopN should return all five items, got %v", name, popped)
+		}
+		if !s.IsEmpty() {
+			t.Errorf("%s: PopN should leave the set empty, got %v", name, s)
+		}
+		if !newSet(1, 2, 3, 4, 5).Has(popped...) {
+			t.Errorf("%s: PopN returned unknown items %v", name, popped)
+		}
+	}
+
+	if popped := NewFair(1, 2, 3).PopN(2); !reflect.DeepEqual(popped, []int{1, 2}) {
+		t.Error("PopN: fair set should pop the oldest items first, got", popped)
+	}
+}
+
+func Test_AddSlice(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":             New[int],
+		"NewNonTS":        NewNonTS[int],
+		"NewFair":         func(items ...int) Set[int] { return NewFair(items...) },
+		"NewNonTSChecked": NewNonTSChecked[int],
+		"NewSorted":       func(items ...int) Set[int] { return NewWithPolicy[int](Sorted).Add(items...) },
+		"NewSharded":      func(items ...int) Set[int] { return NewSharded[int](4).Add(items...) },
+	} {
+		s := newSet(1)
+		if u := s.AddSlice([]int{1, 2, 3, 4}); u != s {
+			t.Errorf("%s: AddSlice should return the set itself", name)
+		}
+		if s.Size() != 4 || !s.Has(1, 2, 3, 4) {
+			t.Errorf("%s: AddSlice should add all the items, got %v", name, s)
+		}
+
+		s.RemoveSlice([]int{1, 3, 5})
+		if s.Size() != 2 || !s.Has(2, 4) {
+			t.Errorf("%s: RemoveSlice should delete the items, got %v", name, s)
+		}
+	}
+
+	if l := NewFair(3).AddSlice([]int{1, 2}).List(); !reflect.DeepEqual(l, []int{3, 1, 2}) {
+		t.Error("AddSlice: fair set should keep the order of adding, got", l)
+	}
+}
+
+func benchmarkAddLarge(b *testing.B, add func(s Set[int], items []int)) {
+	items := make([]int, 1000000)
+	for i := range items {
+		items[i] = i
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		add(NewNonTS[int](), items)
+	}
+}
+
+func BenchmarkAddSlice(b *testing.B) {
+	benchmarkAddLarge(b, func(s Set[int], items []int) { s.AddSlice(items) })
+}
+
+func BenchmarkAddVariadic(b *testing.B) {
+	benchmarkAddLarge(b, func(s Set[int], items []int) { s.Add(items...) })
+}
+
+func Test_Insert(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":             New[int],
+		"NewNonTS":        NewNonTS[int],
+		"NewFair":         func(items ...int) Set[int] { return NewFair(items...) },
+		"NewNonTSChecked": NewNonTSChecked[int],
+		"NewWindowed":     func(items ...int) Set[int] { return NewWindowed[int](10).Add(items...) },
+	} {
+		s := newSet(1)
+		if s.Insert(1) {
+			t.Errorf("%s: Insert of an existing item should return false", name)
+		}
+		if !s.Insert(2) {
+			t.Errorf("%s: Insert of a new item should return true", name)
+		}
+		if s.Size() != 2 || !s.Has(1, 2) {
+			t.Errorf("%s: Insert should add the new item, got %v", name, s)
+		}
+	}
+
+	for name, s := range map[string]Set[hashInt]{
+		"NewAny":      NewAny[hashInt](1),
+		"NewAnyNonTS": NewAnyNonTS[hashInt](1),
+	} {
+		if s.Insert(1) || !s.Insert(2) || s.Size() != 2 {
+			t.Errorf("%s: Insert should add only the new item, got %v", name, s)
+		}
+	}
+
+	s := NewNormalized(strings.ToLower)
+	if !s.Insert("Go") || s.Insert("GO") {
+		t.Error("Insert: normalized duplicates should not be inserted, got", s)
+	}
+}
+
+func Test_NewWithCap(t *testing.T) {
+	for name, s := range map[string]Set[int]{
+		"NewWithCap":      NewWithCap(100, 1, 2, 2),
+		"NewNonTSWithCap": NewNonTSWithCap(100, 1, 2, 2),
+		"NewWithCap_zero": NewWithCap(0, 1, 2, 2),
+	} {
+		if s.Size() != 2 || !s.Has(1, 2) {
+			t.Errorf("%s: expected the items one and two, got %v", name, s)
+		}
+	}
+}
+
+func benchmarkBuild(b *testing.B, newSet func() Set[int]) {
+	const items = 1000000
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		s := newSet()
+		for i := 0; i < items; i++ {
+			s.Add(i)
+		}
+	}
+}
+
+func BenchmarkBuild(b *testing.B) {
+	benchmarkBuild(b, func() Set[int] { return NewNonTS[int]() })
+}
+
+func BenchmarkBuildWithCap(b *testing.B) {
+	benchmarkBuild(b, func() Set[int] { return NewNonTSWithCap[int](1000000) })
+}
+
+// eachCounter counts the traversals of a set.
+type eachCounter struct {
+	Set[int]
+	each int
+}
+
+func (c *eachCounter) Each(f func(int) bool) bool {
+	c.each++
+	return c.Set.Each(f)
+}
+
+func Test_IsSubset_size(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":      New[int],
+		"NewNonTS": NewNonTS[int],
+		"NewBitset": func(items ...int) Set[int] {
+			return NewBitset(0).Add(items...)
+		},
+		"NewSharded": func(items ...int) Set[int] { return NewSharded[int](4).Add(items...) },
+	} {
+		s := newSet(1, 2)
+		bigger := &eachCounter{Set: newNonTS(1, 2, 3)}
+		if s.IsSubset(bigger) || bigger.each != 0 {
+			t.Errorf("%s: IsSubset: bigger set should be rejected without traversing it", name)
+		}
+		if !s.IsSuperset(bigger) {
+			t.Errorf("%s: IsSuperset: bigger set should be a superset", name)
+		}
+
+		equal := newNonTS(1, 2)
+		if !s.IsSubset(equal) || !s.IsSuperset(equal) || !equal.IsSubset(s) {
+			t.Errorf("%s: equal sets should be subsets and supersets of each other", name)
+		}
+		if s.IsSubset(newNonTS(1, 3)) || s.IsSuperset(newNonTS(1, 3)) {
+			t.Errorf("%s: sets of the same size with other items are neither", name)
+		}
+	}
+
+	// several items may normalize to one, so the size doesn't tell
+	s := NewNormalized(strings.ToLower).Add("go")
+	if !s.IsSubset(newNonTS("Go", "GO")) {
+		t.Error("IsSubset: items normalizing to the same one should be found")
+	}
+}
+
+func Test_HasAny(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":        New[int],
+		"NewNonTS":   NewNonTS[int],
+		"NewFair":    func(items ...int) Set[int] { return NewFair(items...) },
+		"NewBitset":  func(items ...int) Set[int] { return NewBitset(0).Add(items...) },
+		"NewSharded": func(items ...int) Set[int] { return NewSharded[int](4).Add(items...) },
+	} {
+		s := newSet(1, 2, 3)
+		if s.HasAny() {
+			t.Errorf("%s: HasAny: should return false if nothing is passed", name)
+		}
+		if !s.HasAny(7, 2, 8) || s.Has(7, 2, 8) {
+			t.Errorf("%s: HasAny: one of many items present should be enough", name)
+		}
+		if s.HasAny(7, 8) {
+			t.Errorf("%s: HasAny: no items present should return false", name)
+		}
+	}
+
+	for name, s := range map[string]Set[hashInt]{
+		"NewAny":      NewAny[hashInt](1),
+		"NewAnyNonTS": NewAnyNonTS[hashInt](1),
+	} {
+		if s.HasAny() || !s.HasAny(2, 1) || s.HasAny(2, 3) {
+			t.Errorf("%s: HasAny: unexpected result", name)
+		}
+	}
+
+	if !NewNormalized(strings.ToLower).Add("go").HasAny("rust", "GO") {
+		t.Error("HasAny: items should be normalized before being looked up")
+	}
+}
+
+func Test_zeroValue(t *testing.T) {
+	for name, s := range map[string]Set[hashInt]{
+		"set":     &set[hashInt]{},
+		"setm":    &setm[hashInt]{},
+		"setAny":  &setAny[hashInt]{},
+		"setAnym": &setAnym[hashInt]{},
+	} {
+		if s.Has(1) || s.Size() != 0 || !s.IsEmpty() || len(s.List()) != 0 || !s.Each(func(hashInt) bool { return false }) {
+			t.Errorf("%s: zero value should behave as an empty set", name)
+		}
+		if _, ok := s.Pop(); ok {
+			t.Errorf("%s: Pop: zero value should have nothing to pop", name)
+		}
+		s.Remove(1)
+
+		if !s.Insert(1) || !s.Add(2).Merge(newNonTS[hashInt](3)).Has(1, 2, 3) || s.Size() != 3 {
+			t.Errorf("%s: zero value should be ready to add items to, got %v", name, s)
+		}
+	}
+
+	s := &setOrdered[int]{}
+	if s.Add(1, 2).Size() != 2 {
+		t.Error("setOrdered: zero value should be ready to add items to, got", s)
+	}
+}
+
+func Test_List_copy(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":             New[int],
+		"NewNonTS":        NewNonTS[int],
+		"NewNonTSWithCap": func(items ...int) Set[int] { return NewNonTSWithCap(100, items...) },
+		"NewFair":         func(items ...int) Set[int] { return NewFair(items...) },
+		"NewWithPolicy":   func(items ...int) Set[int] { return NewWithPolicy[int](Sorted).Add(items...) },
+		"NewBitset":       func(items ...int) Set[int] { return NewBitset(0).Add(items...) },
+		"NewSharded":      func(items ...int) Set[int] { return NewSharded[int](4).Add(items...) },
+		"Freeze":          func(items ...int) Set[int] { return Freeze(New(items...)) },
+	} {
+		testListCopy(t, name, newSet(1, 2, 3), newSet(), 42)
+	}
+
+	for name, newSet := range map[string]func(...hashInt) Set[hashInt]{
+		"NewAny":      NewAny[hashInt],
+		"NewAnyNonTS": NewAnyNonTS[hashInt],
+	} {
+		testListCopy(t, name, newSet(1, 2, 3), newSet(), 42)
+	}
+}
+
+// testListCopy checks that the slice returned by List of s is exactly sized
+// and independent of s, which mustn't have the item other, and that an empty
+// set gives a non-nil slice.
+func testListCopy[T comparable](t *testing.T, name string, s, empty Set[T], other T) {
+	list := s.List()
+	if len(list) != s.Size() || cap(list) != s.Size() {
+		t.Errorf("%s: List: expected length and capacity %d, got %d and %d", name, s.Size(), len(list), cap(list))
+	}
+
+	kept := s.List()
+	for i := range list {
+		list[i] = other
+	}
+	_ = append(list, other)
+	if s.Has(other) || !s.Has(kept...) {
+		t.Errorf("%s: List: modifying the slice should not affect the set, got %v", name, s)
+	}
+
+	if l := empty.List(); l == nil || len(l) != 0 {
+		t.Errorf("%s: List: empty set should give an empty, non-nil slice, got %#v", name, l)
+	}
+}
+
+func Test_Len(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":        New[int],
+		"NewNonTS":   NewNonTS[int],
+		"NewFair":    func(items ...int) Set[int] { return NewFair(items...) },
+		"NewBitset":  func(items ...int) Set[int] { return NewBitset(0).Add(items...) },
+		"NewSharded": func(items ...int) Set[int] { return NewSharded[int](4).Add(items...) },
+	} {
+		for _, s := range []Set[int]{newSet(), newSet(1, 2, 3)} {
+			if s.Len() != s.Size() {
+				t.Errorf("%s: Len: expected %d like Size, got %d", name, s.Size(), s.Len())
+			}
+		}
+	}
+
+	if s := NewAny[hashInt](1, 2); s.Len() != 2 {
+		t.Error("Len: expected two items, got", s.Len())
+	}
+}
+
+func Test_Compact(t *testing.T) {
+	for name, s := range map[string]Set[int]{
+		"New":      New[int](),
+		"NewNonTS": NewNonTS[int](),
+	} {
+		c := s.(Compacter)
+		c.Grow(100000)
+		for i := 0; i < 100000; i++ {
+			s.Add(i)
+		}
+		for i := 1000; i < 100000; i++ {
+			s.Remove(i)
+		}
+
+		c.Compact()
+		if s.Size() != 1000 || !s.Has(0, 999) || s.Has(1000) {
+			t.Errorf("%s: Compact: items should be kept, got %d of them", name, s.Size())
+		}
+
+		s.Add(-1)
+		s.Remove(0)
+		c.Grow(10)
+		if s.Size() != 1000 || !s.Has(-1, 999) || s.Has(0) {
+			t.Errorf("%s: set should keep working after Compact and Grow, got %d items", name, s.Size())
+		}
+	}
+}
+
+func Test_ClearKeepCap(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":             New[int],
+		"NewNonTS":        NewNonTS[int],
+		"NewFair":         func(items ...int) Set[int] { return NewFair(items...) },
+		"NewNonTSChecked": NewNonTSChecked[int],
+	} {
+		s := newSet(1, 2, 3)
+		s.(Compacter).ClearKeepCap()
+		if !s.IsEmpty() || s.Has(1) || len(s.List()) != 0 {
+			t.Errorf("%s: ClearKeepCap: expected an empty set, got %v", name, s)
+		}
+
+		s.Add(4, 5)
+		if s.Size() != 2 || !s.Has(4, 5) || !reflect.DeepEqual(SortedList(s), []int{4, 5}) {
+			t.Errorf("%s: ClearKeepCap: set should be refilled, got %v", name, s)
+		}
+	}
+}
+
+func benchmarkFillClear(b *testing.B, clear func(Set[int])) {
+	s := newNonTS[int]()
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 1000; i++ {
+			s.Insert(i)
+		}
+		clear(s)
+	}
+}
+
+func BenchmarkFillClear(b *testing.B) {
+	benchmarkFillClear(b, func(s Set[int]) { s.Clear() })
+}
+
+func BenchmarkFillClearKeepCap(b *testing.B) {
+	benchmarkFillClear(b, func(s Set[int]) { s.(Compacter).ClearKeepCap() })
+}
+
+func Test_AddSliceCount(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":             New[int],
+		"NewNonTS":        NewNonTS[int],
+		"NewFair":         func(items ...int) Set[int] { return NewFair(items...) },
+		"NewNonTSChecked": NewNonTSChecked[int],
+		"NewSharded":      func(items ...int) Set[int] { return NewSharded[int](4).Add(items...) },
+		"NewBitset":       func(items ...int) Set[int] { return NewBitset(0).Add(items...) },
+		"NewWithPolicy":   func(items ...int) Set[int] { return NewWithPolicy[int](Sorted).Add(items...) },
+	} {
+		s := newSet(1, 2)
+		if n := s.AddSliceCount([]int{2, 3, 3, 4, 1, 4}); n != 2 {
+			t.Errorf("%s: AddSliceCount: expected two new items, got %d", name, n)
+		}
+		if s.Size() != 4 || !s.Has(1, 2, 3, 4) {
+			t.Errorf("%s: AddSliceCount: expected all the items to be added, got %v", name, s)
+		}
+		if n := s.AddSliceCount(nil); n != 0 {
+			t.Errorf("%s: AddSliceCount: expected nothing new, got %d", name, n)
+		}
+	}
+
+	s := NewAny[hashInt](1)
+	if n := s.AddSliceCount([]hashInt{1, 2, 2}); n != 1 || s.Size() != 2 {
+		t.Error("AddSliceCount: expected one new hashable item, got", n)
+	}
+
+	// The windowed set must evict the oldest items, like Add does.
+	w := NewWindowed[int](2)
+	if n := w.AddSliceCount([]int{1, 2, 3}); n != 3 || w.Size() != 2 || w.Has(1) {
+		t.Errorf("AddSliceCount: expected the window to keep [2 3], got %v (%d new)", w, n)
+	}
+
+	// The sorted set must see the new items in its order.
+	p := NewWithPolicy[int](Sorted).Add(3)
+	p.List()
+	if p.AddSliceCount([]int{2, 1}); !slices.Equal(p.List(), []int{1, 2, 3}) {
+		t.Error("AddSliceCount: expected the sorted set to be [1 2 3], got", p.List())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("AddSliceCount: expected a frozen set to panic")
+		}
+	}()
+	Freeze(New(1)).AddSliceCount([]int{2})
+}
+
+func Test_AddSliceCount_concurrent(t *testing.T) {
+	// Overlapping slices are added at once, every item is counted only once.
+	s := New[int]()
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		total int
+	)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			items := make([]int, 0, 100)
+			for i := 0; i < 100; i++ {
+				items = append(items, g*50+i)
+			}
+
+			n := s.AddSliceCount(items)
+			mu.Lock()
+			total += n
+			mu.Unlock()
+		}(g)
+	}
+	wg.Wait()
+
+	if total != s.Size() {
+		t.Errorf("AddSliceCount: counted %d new items, but the set has %d", total, s.Size())
+	}
+}
+
+func Test_RemoveChanged(t *testing.T) {
+	for name, newSet := range map[string]func(...int) Set[int]{
+		"New":        New[int],
+		"NewNonTS":   NewNonTS[int],
+		"NewFair":    func(items ...int) Set[int] { return NewFair(items...) },
+		"NewBitset":  func(items ...int) Set[int] { return NewBitset(0).Add(items...) },
+		"NewSharded": func(items ...int) Set[int] { return NewSharded[int](4).Add(items...) },
+		"NewChecked": NewNonTSChecked[int],
+		"NewSorted":  func(items ...int) Set[int] { return NewWithPolicy[int](Sorted).Add(items...) },
+	} {
+		s := newSet(1, 2, 3)
+		if s.RemoveChanged(4, 5) || s.RemoveChanged() {
+			t.Errorf("%s: RemoveChanged: removing absent items should not change the set", name)
+		}
+		if !s.RemoveChanged(4, 2) || s.Has(2) || s.Size() != 2 {
+			t.Errorf("%s: RemoveChanged: removing a present item should change the set, got %v", name, s)
+		}
+
+		if s.MergeChanged(newNonTS(1, 3)) || s.MergeChanged(s) {
+			t.Errorf("%s: MergeChanged: merging known items should not change the set", name)
+		}
+		if !s.MergeChanged(newTS(3, 4)) || !s.Has(1, 3, 4) || s.Size() != 3 {
+			t.Errorf("%s: MergeChanged: merging a new item should change the set, got %v", name, s)
+		}
+	}
+
+	s := NewAny[hashInt](1)
+	if s.RemoveChanged(2) || !s.MergeChanged(NewAny[hashInt](2)) || !s.RemoveChanged(1) || s.Size() != 1 {
+		t.Error("RemoveChanged: unexpected result for a hashable set, got", s)
+	}
+
+	w := NewWindowed[int](2).Add(1, 2)
+	if !w.MergeChanged(newNonTS(3)) || !w.IsEqual(newNonTS(2, 3)) {
+		t.Error("MergeChanged: evicting an item to merge a new one should change the set, got", w)
+	}
+	if w.MergeChanged(newNonTS(2)) {
+		t.Error("MergeChanged: merging a known item should not change a windowed set, got", w)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RemoveChanged: expected a frozen set to panic")
+		}
+	}()
+	Freeze(New(1)).RemoveChanged(1)
+}
@@ -0,0 +1,83 @@
+package set
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// StringSet wraps a Set of strings to implement encoding.TextMarshaler and
+// encoding.TextUnmarshaler, so it can be used with config libraries reading
+// flags, environment variables or TOML. The text form is a comma-separated
+// list of the items. The zero value is ready to be unmarshaled into, it gets a
+// new threadsafe set.
+type StringSet struct {
+	Set[string]
+}
+
+// MarshalText encodes the items as a comma-separated list, sorted ascending.
+func (s StringSet) MarshalText() ([]byte, error) {
+	if s.Set == nil {
+		return []byte{}, nil
+	}
+
+	return []byte(strings.Join(SortedList(s.Set), ",")), nil
+}
+
+// UnmarshalText replaces the items of the set with the comma-separated items
+// of text. The items are trimmed of surrounding whitespace, empty items are
+// skipped and duplicates collapse.
+func (s *StringSet) UnmarshalText(text []byte) error {
+	if s.Set == nil {
+		s.Set = New[string]()
+	} else {
+		s.Clear()
+	}
+
+	for _, item := range strings.Split(string(text), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			s.Add(item)
+		}
+	}
+
+	return nil
+}
+
+// WriteTo writes the items to w, one per line, sorted ascending, so the
+// output can be read back by ScanLines. It implements io.WriterTo, returning
+// the number of bytes written and the first write error.
+func (s StringSet) WriteTo(w io.Writer) (int64, error) {
+	if s.Set == nil {
+		return 0, nil
+	}
+
+	var written int64
+	for _, item := range SortedList(s.Set) {
+		n, err := io.WriteString(w, item+"\n")
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// ScanLines returns a new threadsafe set with the lines read from r, e.g. an
+// allow-list file. The lines are trimmed of surrounding whitespace, blank
+// lines are skipped and duplicates collapse. An error of reading r is
+// returned along with a nil set.
+func ScanLines(r io.Reader) (Set[string], error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return New[string]().AddSlice(lines), nil
+}
@@ -0,0 +1,120 @@
+package set
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = StringSet{}
+	_ encoding.TextUnmarshaler = (*StringSet)(nil)
+	_ io.WriterTo              = StringSet{}
+)
+
+func TestStringSet_UnmarshalText(t *testing.T) {
+	var s StringSet
+	if err := s.UnmarshalText([]byte("a, b ,a")); err != nil {
+		t.Fatal(err)
+	}
+	if s.Size() != 2 || !s.Has("a", "b") {
+		t.Error("UnmarshalText: expected the items a and b, got", s)
+	}
+
+	if err := s.UnmarshalText([]byte("")); err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsEmpty() {
+		t.Error("UnmarshalText: empty input should give an empty set, got", s)
+	}
+}
+
+func TestStringSet_MarshalText(t *testing.T) {
+	text, err := StringSet{NewNonTS("b", "c", "a")}.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != "a,b,c" {
+		t.Error("MarshalText: expected a sorted comma-separated list, got", string(text))
+	}
+
+	if text, _ := (StringSet{}).MarshalText(); len(text) != 0 {
+		t.Error("MarshalText: zero value should be encoded as an empty string, got", string(text))
+	}
+}
+
+// failingReader returns its data and then err.
+type failingReader struct {
+	data string
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func Test_ScanLines(t *testing.T) {
+	s, err := ScanLines(strings.NewReader("alpha\n\nbeta  \n  alpha\r\n\t\ngamma"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsEqual(newNonTS("alpha", "beta", "gamma")) {
+		t.Error("ScanLines: expected trimmed unique non-blank lines, got", s)
+	}
+
+	readErr := errors.New("disk failure")
+	if s, err := ScanLines(&failingReader{data: "alpha\n", err: readErr}); err != readErr || s != nil {
+		t.Error("ScanLines: expected the read error and no set, got", err, s)
+	}
+}
+
+// failingWriter accepts up to n bytes and then fails with err.
+type failingWriter struct {
+	n   int
+	err error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, w.err
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestStringSet_WriteTo(t *testing.T) {
+	s := StringSet{newTS("beta", "alpha", "gamma")}
+
+	var buf bytes.Buffer
+	n, err := s.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "alpha\nbeta\ngamma\n" || n != int64(buf.Len()) {
+		t.Errorf("WriteTo: expected sorted lines and their length, got %q and %d", buf.String(), n)
+	}
+
+	back, err := ScanLines(&buf)
+	if err != nil || !back.IsEqual(s.Set) {
+		t.Error("WriteTo: output should be read back by ScanLines, got", back, err)
+	}
+
+	writeErr := errors.New("disk full")
+	if n, err := s.WriteTo(&failingWriter{n: 8, err: writeErr}); err != writeErr || n != 8 {
+		t.Errorf("WriteTo: expected the write error after 8 bytes, got %v after %d", err, n)
+	}
+
+	if n, err := (StringSet{}).WriteTo(&buf); n != 0 || err != nil {
+		t.Error("WriteTo: zero value should write nothing, got", n, err)
+	}
+}
@@ -1,7 +1,10 @@
 package set
 
 import (
+	"context"
+	"iter"
 	"sync"
+	"sync/atomic"
 
 	"golang.org/x/exp/maps"
 )
@@ -10,25 +13,142 @@ import (
 type setm[T comparable] struct {
 	set[T]
 	sync.RWMutex // we name it because we don't want to expose it
+	lockOrder
+
+	subs []*subscription[T]
+	wake chan struct{} // closed once items are added, if anyone waits for them
 }
 
-var _ interface {
-	rwLocker
-	Set[int]
-} = (*setm[int])(nil)
+var _ lockedSet[int] = (*setm[int])(nil)
 
 // New creates and initialize a new Set. It's accept a variable number of
 // arguments to populate the initial set. If nothing passed a Set with zero
 // size is created.
-func newTS[T comparable](items ...T) Set[T] {
-	return (&setm[T]{set: set[T]{make(map[T]struct{})}}).Add(items...)
+func newTS[T comparable](items ...T) Set[T] { return newTSWithCap(len(items), items...) }
+
+func newTSWithCap[T comparable](capacity int, items ...T) Set[T] {
+	return &setm[T]{set: *newNonTSWithCap(capacity, items...)}
 }
 
+// SizeAdder is implemented by threadsafe sets, which can report their size
+// right after adding items, without a gap another goroutine could change the
+// set in.
+type SizeAdder[T any] interface {
+	AddAndSize(items ...T) int
+}
+
+var _ SizeAdder[int] = (*setm[int])(nil)
+
+// Claimer is implemented by threadsafe sets, which can test for an item and
+// remove it atomically, so of many workers sharing a set each item is claimed
+// by only one of them.
+type Claimer[T any] interface {
+	// Claim removes the item and returns true if it's in the set, otherwise
+	// false is returned.
+	Claim(item T) bool
+}
+
+var _ Claimer[int] = (*setm[int])(nil)
+
+// PopWaiter is implemented by threadsafe sets, which can wait for an item to
+// pop, e.g. by consumers of a set shared with producers.
+type PopWaiter[T any] interface {
+	PopWait(ctx context.Context) (T, bool)
+}
+
+var _ PopWaiter[int] = (*setm[int])(nil)
+
 type rwLocker interface {
+	sync.Locker
 	RLock()
 	RUnlock()
+	lockID() uint64
+}
+
+// lastLockID is the last ID given to a threadsafe set.
+var lastLockID atomic.Uint64
+
+// lockOrder gives a threadsafe set a unique ID, which defines the order its
+// lock is taken in along with the locks of other sets. The ID is assigned
+// lazily, so a zero value set gets one too.
+type lockOrder struct {
+	id atomic.Uint64
+}
+
+func (o *lockOrder) lockID() uint64 {
+	if id := o.id.Load(); id != 0 {
+		return id
+	}
+	o.id.CompareAndSwap(0, lastLockID.Add(1))
+
+	return o.id.Load()
+}
+
+// lockedSet is a threadsafe set, which exposes its unguarded contents to the
+// callers already holding its lock.
+type lockedSet[T any] interface {
+	Set[T]
+	rwLocker
+	unlocked() Set[T]
+}
+
+// rLockOrdered read-locks both a and b in a canonical order, by their lock
+// IDs, so two goroutines locking the same pair of sets never wait for each
+// other. The returned function releases both locks.
+func rLockOrdered(a, b rwLocker) (unlock func()) {
+	return lockOrdered(a.RLock, a.RUnlock, a, b.RLock, b.RUnlock, b)
+}
+
+// lockWithReader write-locks w and read-locks r in a canonical order, by their
+// lock IDs, like rLockOrdered does. If w and r are the same set, it's only
+// write-locked. The returned function releases both locks.
+func lockWithReader(w, r rwLocker) (unlock func()) {
+	return lockOrdered(w.Lock, w.Unlock, w, r.RLock, r.RUnlock, r)
 }
 
+func lockOrdered(lockA, unlockA func(), a rwLocker, lockB, unlockB func(), b rwLocker) func() {
+	ida, idb := a.lockID(), b.lockID()
+	if ida == idb {
+		lockA()
+		return unlockA
+	}
+	if ida > idb {
+		lockA, unlockA, lockB, unlockB = lockB, unlockB, lockA, unlockA
+	}
+
+	lockA()
+	lockB()
+
+	return func() {
+		unlockB()
+		unlockA()
+	}
+}
+
+// rLockPair read-locks the threadsafe ones of a and b, in a canonical order,
+// and returns their unguarded contents, along with the function releasing the
+// locks.
+func rLockPair[T any](a, b Set[T]) (Set[T], Set[T], func()) {
+	la, aLocked := a.(lockedSet[T])
+	lb, bLocked := b.(lockedSet[T])
+
+	switch {
+	case aLocked && bLocked:
+		unlock := rLockOrdered(la, lb)
+		return la.unlocked(), lb.unlocked(), unlock
+	case aLocked:
+		la.RLock()
+		return la.unlocked(), b, la.RUnlock
+	case bLocked:
+		lb.RLock()
+		return a, lb.unlocked(), lb.RUnlock
+	default:
+		return a, b, func() {}
+	}
+}
+
+func (s *setm[T]) unlocked() Set[T] { return &s.set }
+
 // Add includes the specified items (one or more) to the set. The underlying
 // Set s is modified. If passed nothing it silently returns.
 func (s *setm[T]) Add(items ...T) Set[T] {
@@ -38,11 +158,72 @@ func (s *setm[T]) Add(items ...T) Set[T] {
 
 	s.Lock()
 	defer s.Unlock()
-	s.set.Add()
+	s.add(items...)
+
+	return s
+}
+
+// AddSlice includes the items of the slice to the set, under a single write
+// lock. The map is grown to fit all of them first.
+func (s *setm[T]) AddSlice(items []T) Set[T] {
+	s.Lock()
+	defer s.Unlock()
+	s.set.grow(len(items))
+	s.add(items...)
 
 	return s
 }
 
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setm[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// AddAndSize includes the specified items to the set and returns the size of
+// the set right after, both under the same lock.
+func (s *setm[T]) AddAndSize(items ...T) int {
+	s.Lock()
+	defer s.Unlock()
+	s.add(items...)
+
+	return len(s.m)
+}
+
+// Insert includes the item to the set and reports whether it wasn't there
+// before. The check and the insertion happen under the same write lock, so of
+// the goroutines inserting the same item only one gets true.
+func (s *setm[T]) Insert(item T) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.m[item]; ok {
+		return false
+	}
+	s.add(item)
+
+	return true
+}
+
+// add includes the items to the set. It must be called with the write lock
+// held.
+func (s *setm[T]) add(items ...T) {
+	if len(items) != 0 {
+		s.wakeWaiters()
+	}
+	if len(s.subs) == 0 {
+		s.set.Add(items...)
+		return
+	}
+
+	s.lazyInit()
+	var added []T
+	for _, item := range items {
+		if _, ok := s.m[item]; !ok {
+			s.m[item] = null{}
+			added = append(added, item)
+		}
+	}
+	s.publish(added, nil)
+}
+
 // Remove deletes the specified items from the set.  The underlying Set s is
 // modified. If passed nothing it silently returns.
 func (s *setm[T]) Remove(items ...T) Set[T] {
@@ -52,25 +233,195 @@ func (s *setm[T]) Remove(items ...T) Set[T] {
 
 	s.Lock()
 	defer s.Unlock()
-	s.set.Remove()
+	s.remove(items...)
 
 	return s
 }
 
+// RemoveChanged deletes the items from the set, like Remove, and reports
+// whether any of them was in the set, under the write lock.
+func (s *setm[T]) RemoveChanged(items ...T) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	before := len(s.m)
+	s.remove(items...)
+
+	return len(s.m) != before
+}
+
+// remove deletes the items from the set. It must be called with the write
+// lock held.
+func (s *setm[T]) remove(items ...T) {
+	if len(s.subs) == 0 {
+		s.set.Remove(items...)
+		return
+	}
+
+	var removed []T
+	for _, item := range items {
+		if _, ok := s.m[item]; ok {
+			delete(s.m, item)
+			removed = append(removed, item)
+		}
+	}
+	s.publish(nil, removed)
+}
+
+// AddSliceCount includes the items of the slice to the set and returns the
+// number of the new ones, under the write lock.
+func (s *setm[T]) AddSliceCount(items []T) int {
+	s.Lock()
+	defer s.Unlock()
+
+	s.set.grow(len(items))
+	var added []T
+	for _, item := range items {
+		if s.set.Insert(item) {
+			added = append(added, item)
+		}
+	}
+	if len(added) != 0 {
+		s.publish(added, nil)
+		s.wakeWaiters()
+	}
+
+	return len(added)
+}
+
+// Grow makes room for n more items, under the write lock.
+func (s *setm[T]) Grow(n int) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.set.Grow(n)
+}
+
+// Compact moves the items to a new map of exactly their number, under the
+// write lock.
+func (s *setm[T]) Compact() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.set.Compact()
+}
+
+// Claim removes the item and reports whether it was in the set, both under
+// the same write lock.
+func (s *setm[T]) Claim(item T) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.m[item]; !ok {
+		return false
+	}
+	s.remove(item)
+
+	return true
+}
+
+// RemoveIf deletes every item of s satisfying the predicate. The matching
+// items are collected first and deleted afterwards, all under the write lock.
+func (s *setm[T]) RemoveIf(f func(item T) bool) Set[T] {
+	s.Lock()
+	defer s.Unlock()
+
+	var matched []T
+	for item := range s.m {
+		if f(item) {
+			matched = append(matched, item)
+		}
+	}
+	s.remove(matched...)
+
+	return s
+}
+
+// Retain deletes every item of s not satisfying the predicate.
+func (s *setm[T]) Retain(f func(item T) bool) Set[T] { return s.RemoveIf(not(f)) }
+
 // Pop  deletes and return an item from the set. The underlying Set s is
-// modified. If set is empty, nil is returned.
+// modified. If set is empty, false is returned.
 func (s *setm[T]) Pop() (T, bool) {
-	s.RLock()
-	for item := range s.m {
-		s.RUnlock()
+	// the whole lookup and deletion must happen under the write lock,
+	// otherwise two goroutines could pop the same item.
+	s.Lock()
+	defer s.Unlock()
+
+	item, ok := s.set.Pop()
+	if ok {
+		s.publish(nil, []T{item})
+	}
+
+	return item, ok
+}
+
+// PopN deletes and returns up to n items from the set, all under the same
+// write lock, so concurrent callers never get the same item.
+func (s *setm[T]) PopN(n int) []T {
+	s.Lock()
+	defer s.Unlock()
+
+	items := s.set.PopN(n)
+	s.publish(nil, items)
+
+	return items
+}
+
+// PopWait deletes and returns an item from the set. If the set is empty, it
+// waits until an item is added, or the context is done, in which case false
+// is returned.
+func (s *setm[T]) PopWait(ctx context.Context) (T, bool) {
+	for {
 		s.Lock()
-		delete(s.m, item)
+		item, ok := s.set.Pop()
+		if ok {
+			s.publish(nil, []T{item})
+			s.Unlock()
+			return item, true
+		}
+		wake := s.waitAdded()
 		s.Unlock()
-		return item, true
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			var t T
+			return t, false
+		}
+	}
+}
+
+// waitAdded returns a channel closed once items are added to the set. It must
+// be called with the write lock held.
+func (s *setm[T]) waitAdded() <-chan struct{} {
+	if s.wake == nil {
+		s.wake = make(chan struct{})
+	}
+
+	return s.wake
+}
+
+// wakeWaiters wakes the goroutines waiting for items to be added. It must be
+// called with the write lock held.
+func (s *setm[T]) wakeWaiters() {
+	if s.wake != nil {
+		close(s.wake)
+		s.wake = nil
+	}
+}
+
+// HasAny reports whether at least one of the passed items exists. The set is
+// locked once for all the items.
+func (s *setm[T]) HasAny(items ...T) bool {
+	if len(items) == 0 {
+		return false
 	}
-	s.RUnlock()
-	var t T
-	return t, false
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.set.HasAny(items...)
 }
 
 // Has looks for the existence of items passed. It returns false if nothing is
@@ -102,55 +453,87 @@ func (s *setm[T]) Size() int {
 	return l
 }
 
+// Len is an alias of Size.
+func (s *setm[T]) Len() int { return s.Size() }
+
 // Clear removes all items from the set.
 func (s *setm[T]) Clear() {
 	s.Lock()
 	defer s.Unlock()
+	s.clear()
+}
+
+// ClearKeepCap removes all items from the set, keeping the memory of the map.
+func (s *setm[T]) ClearKeepCap() {
+	s.Lock()
+	defer s.Unlock()
 
+	if len(s.subs) != 0 {
+		s.publish(nil, maps.Keys(s.m))
+	}
+	clear(s.m)
+}
+
+// clear removes all items from the set. It must be called with the write lock
+// held.
+func (s *setm[T]) clear() {
+	if len(s.subs) != 0 {
+		s.publish(nil, maps.Keys(s.m))
+	}
 	s.m = make(map[T]struct{})
 }
 
 // IsEqual test whether s and t are the same in size and have the same items.
 func (s *setm[T]) IsEqual(t Set[T]) bool {
-	s.RLock()
-	defer s.RUnlock()
-
-	// Force locking only if given set is threadsafe.
-	if conv, ok := t.(rwLocker); ok {
-		conv.RLock()
-		defer conv.RUnlock()
+	if t == Set[T](s) {
+		return true // no need to lock, even once
 	}
 
-	// return false if they are no the same size
-	if sameSize := len(s.m) == t.Size(); !sameSize {
-		return false
+	// Lock both sets in a canonical order if given set is threadsafe, so
+	// concurrent s.IsEqual(t) and t.IsEqual(s) can't deadlock.
+	if conv, ok := t.(lockedSet[T]); ok {
+		defer rLockOrdered(s, conv)()
+		return s.set.IsEqual(conv.unlocked())
 	}
 
-	equal := true
-	t.Each(func(item T) bool {
-		_, equal = s.m[item]
-		return equal // if false, Each() will end
-	})
+	s.RLock()
+	defer s.RUnlock()
 
-	return equal
+	return s.set.IsEqual(t)
 }
 
 // IsSubset tests whether t is a subset of s.
 func (s *setm[T]) IsSubset(t Set[T]) bool {
+	// Like IsEqual, lock both sets in a canonical order, as IsSuperset calls
+	// IsSubset the other way round.
+	if conv, ok := t.(lockedSet[T]); ok {
+		defer rLockOrdered(s, conv)()
+		return s.set.IsSubset(conv.unlocked())
+	}
+
 	s.RLock()
 	defer s.RUnlock()
 
-	return t.Each(func(item T) bool {
-		_, ok := s.m[item]
-		return ok
-	})
+	return s.set.IsSubset(t)
 }
 
-func (s *setm[T]) Each(f func(item T) bool) bool {
-	s.RLock()
-	defer s.RUnlock()
+// IsSuperset tests whether t is a superset of s.
+func (s *setm[T]) IsSuperset(t Set[T]) bool { return t.IsSubset(s) }
 
-	return s.set.Each(f)
+// Each traverses the items in the Set, calling the provided function for each
+// set member. Traversal will continue until all items in the Set have been
+// visited, or if the closure returns false.
+func (s *setm[T]) Each(f func(item T) bool) bool { return each(s.All(), f) }
+
+// All returns an iterator over the items in the Set. The read lock is held
+// for the whole iteration.
+func (s *setm[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.RLock()
+		defer s.RUnlock()
+
+		s.set.All()(yield)
+	}
 }
 
 // List returns a slice of all items.
@@ -158,25 +541,87 @@ func (s *setm[T]) List() []T {
 	s.RLock()
 	defer s.RUnlock()
 
-	return maps.Keys(s.m)
+	return s.set.List()
 }
 
+// Copy returns a new Set with a copy of s.
 func (s *setm[T]) Copy() Set[T] {
-	u := newTS[T]()
-	for item := range s.m {
-		u.Add(item)
-	}
-	return u
+	s.RLock()
+	defer s.RUnlock()
+
+	return &setm[T]{set: *s.set.Copy().(*set[T])}
 }
 
+// Filter returns a new Set with the items of s satisfying the predicate. The
+// read lock is held for the whole filtering.
+func (s *setm[T]) Filter(f func(item T) bool) Set[T] {
+	s.RLock()
+	defer s.RUnlock()
+
+	return &setm[T]{set: *s.set.Filter(f).(*set[T])}
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set. If t is threadsafe too, both locks are taken in the
+// canonical order, so concurrent s.Merge(t) and t.Merge(s) can't deadlock.
 func (s *setm[T]) Merge(t Set[T]) Set[T] {
-	s.Lock()
-	defer s.Unlock()
+	s.MergeChanged(t)
+	return s
+}
+
+// MergeChanged merges t into s, like Merge, and reports whether any item was
+// added, under the same locks.
+func (s *setm[T]) MergeChanged(t Set[T]) bool {
+	if conv, ok := t.(lockedSet[T]); ok {
+		defer lockWithReader(s, conv)()
+		t = conv.unlocked()
+	} else {
+		s.Lock()
+		defer s.Unlock()
+	}
 
+	s.lazyInit()
+	before := len(s.m)
+	var added []T
 	t.Each(func(item T) bool {
+		if _, ok := s.m[item]; !ok && len(s.subs) != 0 {
+			added = append(added, item)
+		}
 		s.m[item] = null{}
 		return true
 	})
+	s.publish(added, nil)
+	s.wakeWaiters()
 
-	return s
+	return len(s.m) != before
 }
+
+// copyFrom replaces the items of s with the items of t, keeping the memory of
+// s. Both sets are locked in the canonical order.
+func (s *setm[T]) copyFrom(t Set[T]) {
+	if conv, ok := t.(lockedSet[T]); ok {
+		defer lockWithReader(s, conv)()
+		t = conv.unlocked()
+	} else {
+		s.Lock()
+		defer s.Unlock()
+	}
+
+	if len(s.subs) != 0 {
+		s.publish(nil, maps.Keys(s.m))
+		clear(s.m)
+		s.add(t.List()...)
+		return
+	}
+
+	clear(s.m)
+	s.lazyInit()
+	t.Each(func(item T) bool {
+		s.m[item] = null{}
+		return true
+	})
+	s.wakeWaiters()
+}
+
+// Separate removes the set items containing in t from set s.
+func (s *setm[T]) Separate(t Set[T]) Set[T] { return s.Remove(t.List()...) }
@@ -1,14 +1,17 @@
 package set
 
 import (
+	"context"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestSet_New(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 
 	if s.Size() != 0 {
 		t.Error("New: calling without any parameters should create a set with zero size")
@@ -16,8 +19,8 @@ func TestSet_New(t *testing.T) {
 }
 
 func TestSet_New_parameters(t *testing.T) {
-	s := newTS()
-	s.Add("string", "another_string", 1, 3.14)
+	s := newTS[string]()
+	s.Add("string", "another_string", "1", "3.14")
 
 	if s.Size() != 4 {
 		t.Error("New: calling with parameters should create a set with size of four")
@@ -25,10 +28,10 @@ func TestSet_New_parameters(t *testing.T) {
 }
 
 func TestSet_Add(t *testing.T) {
-	s := newTS()
-	s.Add(1)
-	s.Add(2)
-	s.Add(2) // duplicate
+	s := newTS[string]()
+	s.Add("1")
+	s.Add("2")
+	s.Add("2") // duplicate
 	s.Add("fatih")
 	s.Add("zeynep")
 	s.Add("zeynep") // another duplicate
@@ -37,41 +40,41 @@ func TestSet_Add(t *testing.T) {
 		t.Error("Add: items are not unique. The set size should be four")
 	}
 
-	if !s.Has(1, 2, "fatih", "zeynep") {
+	if !s.Has("1", "2", "fatih", "zeynep") {
 		t.Error("Add: added items are not availabile in the set.")
 	}
 }
 
 func TestSet_Add_multiple(t *testing.T) {
-	s := newTS()
-	s.Add("ankara", "san francisco", 3.14)
+	s := newTS[string]()
+	s.Add("ankara", "san francisco", "3.14")
 
 	if s.Size() != 3 {
 		t.Error("Add: items are not unique. The set size should be three")
 	}
 
-	if !s.Has("ankara", "san francisco", 3.14) {
+	if !s.Has("ankara", "san francisco", "3.14") {
 		t.Error("Add: added items are not availabile in the set.")
 	}
 }
 
 func TestSet_Remove(t *testing.T) {
-	s := newTS()
-	s.Add(1)
-	s.Add(2)
+	s := newTS[string]()
+	s.Add("1")
+	s.Add("2")
 	s.Add("fatih")
 
-	s.Remove(1)
+	s.Remove("1")
 	if s.Size() != 2 {
 		t.Error("Remove: set size should be two after removing")
 	}
 
-	s.Remove(1)
+	s.Remove("1")
 	if s.Size() != 2 {
 		t.Error("Remove: set size should be not change after trying to remove a non-existing item")
 	}
 
-	s.Remove(2)
+	s.Remove("2")
 	s.Remove("fatih")
 	if s.Size() != 0 {
 		t.Error("Remove: set size should be zero")
@@ -81,9 +84,9 @@ func TestSet_Remove(t *testing.T) {
 }
 
 func TestSet_Remove_multiple(t *testing.T) {
-	s := newTS()
-	s.Add("ankara", "san francisco", 3.14, "istanbul")
-	s.Remove("ankara", "san francisco", 3.14)
+	s := newTS[string]()
+	s.Add("ankara", "san francisco", "3.14", "istanbul")
+	s.Remove("ankara", "san francisco", "3.14")
 
 	if s.Size() != 1 {
 		t.Error("Remove: items are not unique. The set size should be four")
@@ -95,12 +98,12 @@ func TestSet_Remove_multiple(t *testing.T) {
 }
 
 func TestSet_Pop(t *testing.T) {
-	s := newTS()
-	s.Add(1)
-	s.Add(2)
+	s := newTS[string]()
+	s.Add("1")
+	s.Add("2")
 	s.Add("fatih")
 
-	a := s.Pop()
+	a, _ := s.Pop()
 	if s.Size() != 2 {
 		t.Error("Pop: set size should be two after popping out")
 	}
@@ -111,16 +114,15 @@ func TestSet_Pop(t *testing.T) {
 
 	s.Pop()
 	s.Pop()
-	b := s.Pop()
-	if b != nil {
-		t.Error("Pop: should return nil because set is empty")
+	if _, ok := s.Pop(); ok {
+		t.Error("Pop: should return false because set is empty")
 	}
 
 	s.Pop() // try to remove something from a zero length set
 }
 
 func TestSet_Has(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3", "4")
 
 	if !s.Has("1") {
@@ -133,8 +135,8 @@ func TestSet_Has(t *testing.T) {
 }
 
 func TestSet_Clear(t *testing.T) {
-	s := newTS()
-	s.Add(1)
+	s := newTS[string]()
+	s.Add("1")
 	s.Add("istanbul")
 	s.Add("san francisco")
 
@@ -145,15 +147,15 @@ func TestSet_Clear(t *testing.T) {
 }
 
 func TestSet_IsEmpty(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 
 	empty := s.IsEmpty()
 	if !empty {
 		t.Error("IsEmpty: set is empty, it should be true")
 	}
 
-	s.Add(2)
-	s.Add(3)
+	s.Add("2")
+	s.Add("3")
 	notEmpty := s.IsEmpty()
 
 	if notEmpty {
@@ -163,9 +165,9 @@ func TestSet_IsEmpty(t *testing.T) {
 
 func TestSet_IsEqual(t *testing.T) {
 	// same size, same content
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3")
-	u := newTS()
+	u := newTS[string]()
 	u.Add("1", "2", "3")
 
 	ok := s.IsEqual(u)
@@ -174,9 +176,9 @@ func TestSet_IsEqual(t *testing.T) {
 	}
 
 	// same size, different content
-	a := newTS()
+	a := newTS[string]()
 	a.Add("1", "2", "3")
-	b := newTS()
+	b := newTS[string]()
 	b.Add("4", "5", "6")
 
 	ok = a.IsEqual(b)
@@ -185,9 +187,9 @@ func TestSet_IsEqual(t *testing.T) {
 	}
 
 	// different size, similar content
-	a = newTS()
+	a = newTS[string]()
 	a.Add("1", "2", "3")
-	b = newTS()
+	b = newTS[string]()
 	b.Add("1", "2", "3", "4")
 
 	ok = a.IsEqual(b)
@@ -197,9 +199,9 @@ func TestSet_IsEqual(t *testing.T) {
 }
 
 func TestSet_IsSubset(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3", "4")
-	u := newTS()
+	u := newTS[string]()
 	u.Add("1", "2", "3")
 
 	ok := s.IsSubset(u)
@@ -214,9 +216,9 @@ func TestSet_IsSubset(t *testing.T) {
 }
 
 func TestSet_IsSuperset(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3", "4")
-	u := newTS()
+	u := newTS[string]()
 	u.Add("1", "2", "3")
 
 	ok := u.IsSuperset(s)
@@ -231,12 +233,12 @@ func TestSet_IsSuperset(t *testing.T) {
 }
 
 func TestSet_String(t *testing.T) {
-	s := newTS()
-	if s.String() != "[]" {
+	s := newTS[string]()
+	if s.String() != "set[]" {
 		t.Errorf("String: output is not what is excepted '%s'", s.String())
 	}
 
-	if !strings.HasPrefix(s.String(), "[") {
+	if !strings.HasPrefix(s.String(), "set[") {
 		t.Error("String: output should begin with a square bracket")
 	}
 
@@ -246,7 +248,7 @@ func TestSet_String(t *testing.T) {
 }
 
 func TestSet_List(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3", "4")
 
 	// this returns a slice of interface{}
@@ -263,7 +265,7 @@ func TestSet_List(t *testing.T) {
 }
 
 func TestSet_Copy(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3", "4")
 	r := s.Copy()
 
@@ -273,9 +275,9 @@ func TestSet_Copy(t *testing.T) {
 }
 
 func TestSet_Merge(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3")
-	r := newTS()
+	r := newTS[string]()
 	r.Add("3", "4", "5")
 	s.Merge(r)
 
@@ -289,9 +291,9 @@ func TestSet_Merge(t *testing.T) {
 }
 
 func TestSet_Separate(t *testing.T) {
-	s := newTS()
+	s := newTS[string]()
 	s.Add("1", "2", "3")
-	r := newTS()
+	r := newTS[string]()
 	r.Add("3", "5")
 	s.Separate(r)
 
@@ -308,8 +310,8 @@ func TestSet_RaceAdd(t *testing.T) {
 	// Create two sets. Add concurrently items to each of them. Remove from the
 	// other one.
 	// "go test -race" should detect this if the library is not thread-safe.
-	s := newTS()
-	u := newTS()
+	s := newTS[string]()
+	u := newTS[string]()
 
 	go func() {
 		for i := 0; i < 1000; i++ {
@@ -329,3 +331,419 @@ func TestSet_RaceAdd(t *testing.T) {
 		}(i)
 	}
 }
+
+func TestSet_IsEqual_crossed(t *testing.T) {
+	// Compare two sets in both directions while writers contend for their
+	// locks. "go test -race" checks the safety, the deadline the liveness.
+	a := newTS[int](1, 2, 3)
+	b := newTS[int](1, 2, 3)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+				a.IsEqual(b)
+			}()
+			go func() {
+				defer wg.Done()
+				b.IsEqual(a)
+			}()
+			go func(i int) {
+				defer wg.Done()
+				a.Add(i)
+				b.Add(i)
+			}(i)
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("IsEqual: crossed comparisons deadlocked")
+	}
+
+	if !a.IsEqual(b) || !b.IsEqual(a) {
+		t.Error("IsEqual: sets with the same items should be equal")
+	}
+}
+
+func TestSet_IsEqual_self(t *testing.T) {
+	// Compare sets to themselves while a writer contends for their locks.
+	for name, s := range map[string]Set[hashInt]{
+		"New":    New[hashInt](1, 2, 3),
+		"NewAny": NewAny[hashInt](1, 2, 3),
+	} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 100; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					if !s.IsEqual(s) {
+						t.Errorf("%s: IsEqual: set should be equal to itself", name)
+					}
+				}()
+				go func(i int) {
+					defer wg.Done()
+					s.Add(hashInt(i))
+				}(i)
+			}
+			wg.Wait()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%s: IsEqual: comparing a set to itself deadlocked", name)
+		}
+	}
+}
+
+func TestSet_Pop_concurrent(t *testing.T) {
+	// Many goroutines drain a shared set, no item may be returned twice.
+	const items = 1000
+
+	s := newTS[int]()
+	for i := 0; i < items; i++ {
+		s.Add(i)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		popped = make(map[int]int, items)
+	)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				item, ok := s.Pop()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				popped[item]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(popped) != items {
+		t.Errorf("Pop: expected %d distinct items, got %d", items, len(popped))
+	}
+	for item, n := range popped {
+		if n != 1 {
+			t.Errorf("Pop: item %d returned %d times", item, n)
+		}
+	}
+}
+
+func TestSet_AddAndSize(t *testing.T) {
+	s := newTS[string]("a")
+
+	if size := s.(SizeAdder[string]).AddAndSize("b", "c"); size != 3 {
+		t.Error("AddAndSize: expected the size of three, got", size)
+	}
+
+	if size := s.(SizeAdder[string]).AddAndSize("a"); size != 3 {
+		t.Error("AddAndSize: existing item should not change the size, got", size)
+	}
+}
+
+func TestSet_PopN_concurrent(t *testing.T) {
+	// Goroutines drain a shared set in batches, no item may be returned twice.
+	const items = 1000
+
+	s := newTS[int]()
+	for i := 0; i < items; i++ {
+		s.Add(i)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		popped = make(map[int]int, items)
+	)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				batch := s.PopN(7)
+				if len(batch) == 0 {
+					return
+				}
+				mu.Lock()
+				for _, item := range batch {
+					popped[item]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(popped) != items {
+		t.Errorf("PopN: expected %d distinct items, got %d", items, len(popped))
+	}
+	for item, n := range popped {
+		if n != 1 {
+			t.Errorf("PopN: item %d returned %d times", item, n)
+		}
+	}
+}
+
+func TestSet_Insert_concurrent(t *testing.T) {
+	// Many goroutines insert the same new item, only one may see it as new.
+	s := newTS[string]()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		inserted int
+	)
+	for g := 0; g < 64; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.Insert("item") {
+				mu.Lock()
+				inserted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if inserted != 1 {
+		t.Error("Insert: expected exactly one true, got", inserted)
+	}
+}
+
+func TestSet_Claim(t *testing.T) {
+	// Many workers claim the same item, only one may succeed.
+	for name, s := range map[string]Set[hashInt]{
+		"New":    newTS[hashInt](1),
+		"NewAny": newAnyTS[hashInt](1),
+	} {
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			claimed int
+		)
+		for g := 0; g < 64; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if s.(Claimer[hashInt]).Claim(1) {
+					mu.Lock()
+					claimed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if claimed != 1 {
+			t.Errorf("%s: Claim: expected exactly one success, got %d", name, claimed)
+		}
+		if !s.IsEmpty() {
+			t.Errorf("%s: Claim: claimed item should be removed, got %v", name, s)
+		}
+	}
+}
+
+func TestSet_crossed(t *testing.T) {
+	// Merge and compare two sets in both directions at once. "go test -race"
+	// checks the safety, the deadline the liveness.
+	for name, newSet := range map[string]func(...hashInt) Set[hashInt]{
+		"New":    New[hashInt],
+		"NewAny": NewAny[hashInt],
+	} {
+		a, b := newSet(), newSet()
+		for i := 0; i < 1000; i++ {
+			a.Add(hashInt(i))
+			b.Add(hashInt(i + 500))
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 100; i++ {
+				wg.Add(4)
+				go func() {
+					defer wg.Done()
+					a.Merge(b)
+				}()
+				go func() {
+					defer wg.Done()
+					b.Merge(a)
+				}()
+				go func() {
+					defer wg.Done()
+					a.IsEqual(b)
+				}()
+				go func(i int) {
+					defer wg.Done()
+					b.IsEqual(a)
+					a.Add(hashInt(-i))
+				}(i)
+			}
+			wg.Wait()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%s: crossed Merge and IsEqual deadlocked", name)
+		}
+
+		a.Merge(b)
+		b.Merge(a)
+		if !a.IsEqual(b) {
+			t.Errorf("%s: sets merged both ways should be equal", name)
+		}
+	}
+}
+
+func TestSet_crossedAlgebra(t *testing.T) {
+	// Combine two sets in both directions while writers contend for their
+	// locks. "go test -race" checks the safety, the deadline the liveness.
+	for name, newSet := range map[string]func(...hashInt) Set[hashInt]{
+		"New":    New[hashInt],
+		"NewAny": NewAny[hashInt],
+	} {
+		a, b := newSet(), newSet()
+		for i := 0; i < 1000; i++ {
+			a.Add(hashInt(i))
+			b.Add(hashInt(i + 500))
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 100; i++ {
+				wg.Add(3)
+				go func() {
+					defer wg.Done()
+					Union(a, b)
+					Difference(a, b)
+					Intersection(a, b)
+				}()
+				go func() {
+					defer wg.Done()
+					Union(b, a)
+					Difference(b, a)
+					Intersection(b, a)
+				}()
+				go func(i int) {
+					defer wg.Done()
+					a.Add(hashInt(-i))
+					b.Remove(hashInt(i + 500))
+				}(i)
+			}
+			wg.Wait()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%s: crossed Union, Difference and Intersection deadlocked", name)
+		}
+
+		if u := Intersection(a, b); u.Size() != 400 {
+			t.Errorf("%s: Intersection: expected 400 common items, got %d", name, u.Size())
+		}
+	}
+}
+
+func TestSet_EachSnapshot(t *testing.T) {
+	s := newTS(1, 2, 3)
+
+	done := make(chan bool)
+	go func() {
+		done <- EachSnapshot(s, func(item int) bool {
+			s.Remove(item)
+			s.Add(item * 10)
+			return true
+		})
+	}()
+
+	select {
+	case completed := <-done:
+		if !completed {
+			t.Error("EachSnapshot: all the items should be visited")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("EachSnapshot: modifying the set from the callback deadlocked")
+	}
+
+	if !s.IsEqual(newNonTS(10, 20, 30)) {
+		t.Error("EachSnapshot: callback should visit the snapshot only, got", s)
+	}
+
+	visited := 0
+	if EachSnapshot(s, func(int) bool { visited++; return false }) || visited != 1 {
+		t.Error("EachSnapshot: should stop when the callback returns false")
+	}
+}
+
+func TestSet_PopWait(t *testing.T) {
+	persistent, err := NewPersistent[int](&memoryKV{data: map[string][]byte{}}, "work/")
+	if err != nil {
+		t.Fatal("NewPersistent:", err)
+	}
+
+	for name, s := range map[string]Set[int]{
+		"New":           newTS[int](),
+		"NewPersistent": persistent,
+	} {
+		got := make(chan int)
+		go func() {
+			item, ok := s.(PopWaiter[int]).PopWait(context.Background())
+			if !ok {
+				t.Errorf("%s: PopWait: should not fail without cancellation", name)
+			}
+			got <- item
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		s.Add(42)
+
+		select {
+		case item := <-got:
+			if item != 42 || !s.IsEmpty() {
+				t.Errorf("%s: PopWait: expected to pop the added item, got %d and %v", name, item, s)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%s: PopWait: not woken by Add", name)
+		}
+
+		s.Add(7)
+		if item, ok := s.(PopWaiter[int]).PopWait(context.Background()); !ok || item != 7 {
+			t.Errorf("%s: PopWait: should return an available item at once, got %d", name, item)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		item, ok := s.(PopWaiter[int]).PopWait(ctx)
+		cancel()
+		if ok || item != 0 {
+			t.Errorf("%s: PopWait: expected zero and false on cancellation, got %d", name, item)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+)
+
+type (
+	userID  int
+	orderID int
+)
+
+func Test_TypedIDs(t *testing.T) {
+	users := newNonTS[userID](1, 2, 3)
+	orders := newNonTS[orderID](1, 2, 3)
+
+	// Sets of different element types can't be mixed, even if the underlying
+	// types are the same. This doesn't compile:
+	//
+	//	Union(users, orders)
+	userSet := reflect.TypeOf((*Set[userID])(nil)).Elem()
+	orderSet := reflect.TypeOf((*Set[orderID])(nil)).Elem()
+	if userSet.AssignableTo(orderSet) || reflect.TypeOf(users).AssignableTo(orderSet) {
+		t.Error("Set[userID] should not be assignable to Set[orderID]")
+	}
+
+	u := Union(users, newTS[userID](4))
+	if u.Size() != 4 || !u.Has(1, 2, 3, 4) {
+		t.Error("Union: typed id sets should be merged as usual, got", u)
+	}
+
+	i := Intersection(orders, newTS[orderID](2, 3, 4))
+	if i.Size() != 2 || !i.Has(2, 3) {
+		t.Error("Intersection: typed id sets should be intersected as usual, got", i)
+	}
+}
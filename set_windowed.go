@@ -0,0 +1,92 @@
+package set
+
+// setWindowed is a non-threadsafe set, which holds only the most recently
+// added distinct items.
+type setWindowed[T comparable] struct {
+	setOrdered[T]
+	window int
+}
+
+var _ Set[int] = (*setWindowed[int])(nil)
+
+// NewWindowed creates a new non-threadsafe Set, which holds at most window
+// distinct items: once it's full, adding a new item evicts the least recently
+// added one, which then can be added again as new. Adding an item which is
+// already in the set makes it the most recent one again. It suits
+// deduplication of recurring values, like log messages. The window must be
+// positive.
+func NewWindowed[T comparable](window int) Set[T] {
+	if window <= 0 {
+		panic("set: window of NewWindowed must be positive")
+	}
+
+	return &setWindowed[T]{
+		setOrdered: setOrdered[T]{set: set[T]{make(map[T]struct{}, window)}},
+		window:     window,
+	}
+}
+
+// Add includes the specified items (one or more) to the set, evicting the
+// least recently added items beyond the window. The underlying Set s is
+// modified. If passed nothing it silently returns.
+func (s *setWindowed[T]) Add(items ...T) Set[T] {
+	for _, item := range items {
+		s.setOrdered.Remove(item) // refresh the position of an existing item
+		s.setOrdered.Add(item)
+		for s.Size() > s.window {
+			s.PopFair()
+		}
+	}
+
+	return s
+}
+
+// AddSlice includes the items of the slice to the set.
+func (s *setWindowed[T]) AddSlice(items []T) Set[T] { return s.Add(items...) }
+
+// RemoveSlice deletes the items of the slice from the set.
+// AddSliceCount includes the items of the slice to the set, evicting the
+// oldest ones as needed, and returns the number of the new ones.
+func (s *setWindowed[T]) AddSliceCount(items []T) int { return insertCount[T](s, items) }
+
+// RemoveChanged deletes the items from the set and reports whether any of them
+// was in the set.
+func (s *setWindowed[T]) RemoveChanged(items ...T) bool { return removeChanged[T](s, items) }
+
+// MergeChanged merges t into s, evicting the oldest items as needed, and
+// reports whether any item was added.
+func (s *setWindowed[T]) MergeChanged(t Set[T]) bool { return mergeChanged[T](s, t) }
+
+// RemoveSlice deletes the items of the slice from the set.
+func (s *setWindowed[T]) RemoveSlice(items []T) Set[T] { return s.Remove(items...) }
+
+// Insert is like Add for a single item, and reports whether the item wasn't
+// in the set before.
+func (s *setWindowed[T]) Insert(item T) bool {
+	inserted := !s.Has(item)
+	s.Add(item)
+
+	return inserted
+}
+
+// Copy returns a new Set with a copy of s.
+func (s *setWindowed[T]) Copy() Set[T] {
+	u := NewWindowed[T](s.window)
+	u.Add(s.order[s.head:]...)
+	return u
+}
+
+// Filter returns a new Set with the items of s satisfying the predicate.
+func (s *setWindowed[T]) Filter(f func(item T) bool) Set[T] {
+	u := NewWindowed[T](s.window)
+	for _, item := range s.order[s.head:] {
+		if f(item) {
+			u.Add(item)
+		}
+	}
+	return u
+}
+
+// Merge is like Union, however it modifies the current set it's applied on
+// with the given t set.
+func (s *setWindowed[T]) Merge(t Set[T]) Set[T] { return s.Add(t.List()...) }
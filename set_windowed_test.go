@@ -0,0 +1,29 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetWindowed(t *testing.T) {
+	s := NewWindowed[int](3)
+	s.Add(1, 2, 3, 4)
+
+	if s.Has(1) {
+		t.Error("Add: oldest item should fall out of the window, got", s)
+	}
+	if s.Size() != 3 || !s.Has(2, 3, 4) {
+		t.Error("Add: the last three items should be kept, got", s)
+	}
+
+	s.Add(2) // refreshed, now 3 is the oldest
+	s.Add(1) // added as new again
+	if got := s.List(); !reflect.DeepEqual(got, []int{4, 2, 1}) {
+		t.Error("Add: expected the three most recent items, got", got)
+	}
+
+	s.Merge(newNonTS(7, 8))
+	if s.Size() != 3 {
+		t.Error("Merge: window should be respected, got", s)
+	}
+}
@@ -0,0 +1,38 @@
+// Package settest provides helpers for testing code working with sets.
+package settest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/quenbyako/set"
+)
+
+// AssertEqual fails the test if got and want don't hold the same items. The
+// failure message lists the items got has in excess of want, and the items
+// it misses.
+func AssertEqual[T comparable](t testing.TB, got, want set.Set[T]) {
+	t.Helper()
+
+	extra := set.Difference(got, want)
+	missing := set.Difference(want, got)
+	if extra.IsEmpty() && missing.IsEmpty() {
+		return
+	}
+
+	t.Errorf("sets are not equal:\n\textra:   %s\n\tmissing: %s", listItems(extra), listItems(missing))
+}
+
+// listItems formats the items of s in a sorted order, so messages are stable.
+func listItems[T comparable](s set.Set[T]) string {
+	items := make([]string, 0, s.Size())
+	s.Each(func(item T) bool {
+		items = append(items, fmt.Sprint(item))
+		return true
+	})
+	sort.Strings(items)
+
+	return "[" + strings.Join(items, ", ") + "]"
+}
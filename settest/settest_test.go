@@ -0,0 +1,42 @@
+package settest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/quenbyako/set"
+)
+
+// recorder captures the failures reported to it.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEqual(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertEqual(r, set.New(1, 2, 3), set.NewNonTS(3, 2, 1))
+	if len(r.errors) != 0 {
+		t.Error("AssertEqual: equal sets should pass, got", r.errors)
+	}
+
+	AssertEqual(r, set.New(1, 2, 3, 5), set.NewNonTS(1, 4, 6))
+	if len(r.errors) != 1 {
+		t.Fatal("AssertEqual: different sets should fail once, got", r.errors)
+	}
+
+	msg := r.errors[0]
+	if !strings.Contains(msg, "extra:   [2, 3, 5]") {
+		t.Error("AssertEqual: message should list the extra items, got", msg)
+	}
+	if !strings.Contains(msg, "missing: [4, 6]") {
+		t.Error("AssertEqual: message should list the missing items, got", msg)
+	}
+}